@@ -0,0 +1,3 @@
+data "woodpecker_agent" "test_agent" {
+  id = 1
+}
@@ -0,0 +1,12 @@
+data "woodpecker_agents" "all" {}
+
+data "woodpecker_org" "test_org" {
+  name = "test"
+}
+
+data "woodpecker_agents" "test_org_europe" {
+  org_id = data.woodpecker_org.test_org.id
+  custom_labels = {
+    "location" = "europe"
+  }
+}
@@ -0,0 +1,3 @@
+data "woodpecker_global_registry" "test_registry" {
+  address = "docker.io"
+}
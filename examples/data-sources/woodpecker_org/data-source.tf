@@ -1,3 +1,7 @@
 data "woodpecker_org" "test_org" {
   name = "test"
 }
+
+data "woodpecker_org" "test_org_by_id" {
+  id = 1
+}
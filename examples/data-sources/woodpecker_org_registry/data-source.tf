@@ -0,0 +1,8 @@
+data "woodpecker_org" "test_org" {
+  name = "test"
+}
+
+data "woodpecker_org_registry" "test_registry" {
+  org_id  = data.woodpecker_org.test_org.id
+  address = "docker.io"
+}
@@ -0,0 +1,8 @@
+data "woodpecker_org" "test_org" {
+  name = "test"
+}
+
+data "woodpecker_org_secrets" "test_org" {
+  org_id      = data.woodpecker_org.test_org.id
+  name_prefix = "docker_"
+}
@@ -0,0 +1,5 @@
+data "woodpecker_orgs" "all" {}
+
+data "woodpecker_orgs" "prefix" {
+  name_prefix = "team-"
+}
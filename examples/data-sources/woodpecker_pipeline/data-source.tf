@@ -0,0 +1,13 @@
+data "woodpecker_repository" "test_repo" {
+  full_name = "test/test"
+}
+
+data "woodpecker_pipeline" "latest" {
+  repository_id = data.woodpecker_repository.test_repo.id
+  branch        = "main"
+}
+
+data "woodpecker_pipeline" "by_number" {
+  repository_id = data.woodpecker_repository.test_repo.id
+  number        = 42
+}
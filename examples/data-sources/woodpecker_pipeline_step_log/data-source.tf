@@ -0,0 +1,14 @@
+data "woodpecker_repository" "test_repo" {
+  full_name = "test/test"
+}
+
+data "woodpecker_pipeline" "latest" {
+  repository_id = data.woodpecker_repository.test_repo.id
+}
+
+data "woodpecker_pipeline_step_log" "build" {
+  repository_id   = data.woodpecker_repository.test_repo.id
+  pipeline_number = data.woodpecker_pipeline.latest.number
+  step_name       = "build"
+  tail            = 50
+}
@@ -0,0 +1,11 @@
+data "woodpecker_repository" "test_repo" {
+  full_name = "test/test"
+}
+
+data "woodpecker_pipelines" "failed" {
+  repository_id = data.woodpecker_repository.test_repo.id
+  branch        = "main"
+  events        = ["push", "manual"]
+  status        = "failure"
+  limit         = 10
+}
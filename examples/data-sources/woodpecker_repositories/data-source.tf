@@ -0,0 +1,4 @@
+data "woodpecker_repositories" "test_org_active" {
+  name_prefix = "test-org/"
+  active_only = true
+}
@@ -0,0 +1,7 @@
+data "woodpecker_repository" "test_repo" {
+  full_name = "test/test"
+}
+
+data "woodpecker_repository_crons" "test_repo" {
+  repository_id = data.woodpecker_repository.test_repo.id
+}
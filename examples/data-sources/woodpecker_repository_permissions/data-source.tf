@@ -0,0 +1,21 @@
+data "woodpecker_repository" "test_repo" {
+  full_name = "test/test"
+}
+
+data "woodpecker_repository_permissions" "test_repo" {
+  repository_id = data.woodpecker_repository.test_repo.id
+}
+
+resource "woodpecker_repository_secret" "test" {
+  repository_id = data.woodpecker_repository.test_repo.id
+  name          = "test"
+  value         = "test"
+  events        = ["push"]
+
+  lifecycle {
+    precondition {
+      condition     = data.woodpecker_repository_permissions.test_repo.admin
+      error_message = "The provider's token must belong to an admin of the repository."
+    }
+  }
+}
@@ -0,0 +1,8 @@
+data "woodpecker_repository" "test_repo" {
+  full_name = "test/test"
+}
+
+data "woodpecker_repository_registries" "test_repo" {
+  repository_id  = data.woodpecker_repository.test_repo.id
+  address_prefix = "docker.io"
+}
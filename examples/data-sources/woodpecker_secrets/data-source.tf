@@ -0,0 +1,3 @@
+data "woodpecker_secrets" "docker" {
+  name_prefix = "docker_"
+}
@@ -0,0 +1 @@
+terraform import woodpecker_agent.test "<id>"
@@ -0,0 +1,7 @@
+resource "woodpecker_agent" "test" {
+  name        = "test"
+  no_schedule = false
+  custom_labels = {
+    "location" = "europe"
+  }
+}
@@ -0,0 +1 @@
+terraform import woodpecker_deployment.production "<repository_id>/<number>"
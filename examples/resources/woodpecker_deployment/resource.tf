@@ -0,0 +1,20 @@
+resource "woodpecker_repository" "test_repo" {
+  full_name         = "Kichiyaki/test-repo"
+  allow_deployments = true
+}
+
+variable "release_commit" {
+  type = string
+}
+
+resource "woodpecker_deployment" "production" {
+  repository_id       = woodpecker_repository.test_repo.id
+  commit              = var.release_commit
+  environment         = "production"
+  wait_for_completion = true
+  timeout             = 30
+
+  params = {
+    DRY_RUN = "false"
+  }
+}
@@ -0,0 +1 @@
+terraform import woodpecker_global_registry.test "<address>"
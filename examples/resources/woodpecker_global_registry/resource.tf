@@ -0,0 +1,21 @@
+# Create a global registry
+resource "woodpecker_global_registry" "test" {
+  address  = "docker.io"
+  username = "test"
+  password = "test"
+}
+
+# Supply the password as a write-only attribute so it's never persisted in state
+# (requires Terraform 1.11+ or OpenTofu 1.11+). Bump password_wo_version whenever
+# the password changes to push the new value to Woodpecker.
+variable "registry_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "woodpecker_global_registry" "write_only" {
+  address             = "ghcr.io"
+  username            = "test"
+  password_wo         = var.registry_password
+  password_wo_version = 1
+}
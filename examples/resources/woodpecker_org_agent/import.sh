@@ -0,0 +1 @@
+terraform import woodpecker_org_agent.test "<org_id>/<id>"
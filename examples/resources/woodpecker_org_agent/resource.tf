@@ -0,0 +1,8 @@
+resource "woodpecker_org_agent" "test" {
+  org_id      = 1
+  name        = "test"
+  no_schedule = false
+  custom_labels = {
+    "location" = "europe"
+  }
+}
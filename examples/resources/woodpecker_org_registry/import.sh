@@ -0,0 +1 @@
+terraform import woodpecker_org_registry.test "<org_id>/<address>"
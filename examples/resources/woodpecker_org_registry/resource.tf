@@ -0,0 +1,26 @@
+data "woodpecker_org" "test_org" {
+  name = "test"
+}
+
+resource "woodpecker_org_registry" "test" {
+  org_id   = data.woodpecker_org.test_org.id
+  address  = "docker.io"
+  username = "test"
+  password = "test"
+}
+
+# Supply the password as a write-only attribute so it's never persisted in state
+# (requires Terraform 1.11+ or OpenTofu 1.11+). Bump password_wo_version whenever
+# the password changes to push the new value to Woodpecker.
+variable "registry_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "woodpecker_org_registry" "write_only" {
+  org_id              = data.woodpecker_org.test_org.id
+  address             = "ghcr.io"
+  username            = "test"
+  password_wo         = var.registry_password
+  password_wo_version = 1
+}
@@ -0,0 +1 @@
+terraform import woodpecker_pipeline.migrations "<repository_id>/<number>"
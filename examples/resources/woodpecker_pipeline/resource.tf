@@ -0,0 +1,21 @@
+resource "woodpecker_repository" "test_repo" {
+  full_name  = "Kichiyaki/test-repo"
+  is_trusted = true
+  visibility = "public"
+}
+
+resource "woodpecker_pipeline" "migrations" {
+  repository_id       = woodpecker_repository.test_repo.id
+  branch              = "main"
+  wait_for_completion = true
+  timeout             = 30
+
+  variables = {
+    TASK = "migrate"
+  }
+
+  # start a new pipeline whenever the database schema version changes
+  triggers = {
+    schema_version = "42"
+  }
+}
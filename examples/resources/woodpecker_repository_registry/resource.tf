@@ -1,12 +1,27 @@
 resource "woodpecker_repository" "test_repo" {
   full_name  = "Kichiyaki/test-repo"
-  is_trusted = true
   visibility = "public"
 }
 
-resource "woodpecker_repository_secret" "test" {
+resource "woodpecker_repository_registry" "test" {
   repository_id = woodpecker_repository.test_repo.id
   address       = "docker.io"
   username      = "test"
   password      = "test"
 }
+
+# Supply the password as a write-only attribute so it's never persisted in state
+# (requires Terraform 1.11+ or OpenTofu 1.11+). Bump password_wo_version whenever
+# the password changes to push the new value to Woodpecker.
+variable "registry_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "woodpecker_repository_registry" "write_only" {
+  repository_id       = woodpecker_repository.test_repo.id
+  address             = "ghcr.io"
+  username            = "test"
+  password_wo         = var.registry_password
+  password_wo_version = 1
+}
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type agentDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*agentDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*agentDataSource)(nil)
+
+func newAgentDataSource() datasource.DataSource {
+	return &agentDataSource{}
+}
+
+func (d *agentDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_agent"
+}
+
+func (d *agentDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about an agent" +
+			" and the tasks it is currently running.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the agent's id",
+			},
+			"org_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the ID of the organization the agent is registered in, -1 for global agents",
+			},
+			"owner_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the ID of the user who registered the agent",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "the name of the agent",
+			},
+			"platform": schema.StringAttribute{
+				Computed:    true,
+				Description: "the platform reported by the agent (e.g. linux/amd64)",
+			},
+			"backend": schema.StringAttribute{
+				Computed:    true,
+				Description: "the backend reported by the agent (e.g. docker, kubernetes, local)",
+			},
+			"capacity": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the number of workflows the agent can run in parallel",
+			},
+			"version": schema.StringAttribute{
+				Computed:    true,
+				Description: "the version reported by the agent",
+			},
+			"last_contact": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the time the agent last contacted the server (unix timestamp)",
+			},
+			"no_schedule": schema.BoolAttribute{
+				Computed:    true,
+				Description: "whether new tasks are not scheduled on this agent",
+			},
+			"custom_labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "custom labels assigned to the agent",
+			},
+			"tasks": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the tasks the agent is currently running",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "the task's id",
+						},
+						"labels": schema.MapAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "labels used to match the task with an agent",
+						},
+						"dependencies": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "the names of the workflows this task depends on",
+						},
+						"run_on": schema.ListAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "the dependency statuses the task runs on (e.g. success, failure)",
+						},
+						"dep_status": schema.MapAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "the current status of each dependency",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *agentDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *agentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data agentDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := d.client.WithContext(ctx).Agent(data.ID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get agent data", err.Error())
+		return
+	}
+
+	tasks, err := d.client.WithContext(ctx).AgentTasksList(agent.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't list agent tasks", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, agent, tasks)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
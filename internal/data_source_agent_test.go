@@ -0,0 +1,49 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAgentDataSource(t *testing.T) {
+	t.Parallel()
+
+	name := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_agent" "test_agent" {
+	name = "%s"
+	custom_labels = {
+		"location" = "europe"
+	}
+}
+
+data "woodpecker_agent" "test_agent" {
+	id = woodpecker_agent.test_agent.id
+}
+`, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.woodpecker_agent.test_agent",
+						"id",
+						"woodpecker_agent.test_agent",
+						"id",
+					),
+					resource.TestCheckResourceAttr("data.woodpecker_agent.test_agent", "name", name),
+					resource.TestCheckResourceAttr("data.woodpecker_agent.test_agent", "no_schedule", "false"),
+					resource.TestCheckResourceAttr("data.woodpecker_agent.test_agent", "custom_labels.%", "1"),
+					resource.TestCheckResourceAttr("data.woodpecker_agent.test_agent", "custom_labels.location", "europe"),
+					resource.TestCheckResourceAttr("data.woodpecker_agent.test_agent", "tasks.#", "0"),
+				),
+			},
+		},
+	})
+}
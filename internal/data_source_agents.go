@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type agentsDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*agentsDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*agentsDataSource)(nil)
+
+func newAgentsDataSource() datasource.DataSource {
+	return &agentsDataSource{}
+}
+
+func (d *agentsDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_agents"
+}
+
+func (d *agentsDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about registered agents.",
+		Attributes: map[string]schema.Attribute{
+			"org_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "return only agents registered in the organization with the given ID",
+			},
+			"custom_labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "return only agents that have all of the given custom labels",
+			},
+			"agents": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the list of agents",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the agent's id",
+						},
+						"org_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the ID of the organization the agent is registered in, -1 for global agents",
+						},
+						"owner_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the ID of the user who registered the agent",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "the name of the agent",
+						},
+						"platform": schema.StringAttribute{
+							Computed:    true,
+							Description: "the platform reported by the agent (e.g. linux/amd64)",
+						},
+						"backend": schema.StringAttribute{
+							Computed:    true,
+							Description: "the backend reported by the agent (e.g. docker, kubernetes, local)",
+						},
+						"capacity": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the number of workflows the agent can run in parallel",
+						},
+						"version": schema.StringAttribute{
+							Computed:    true,
+							Description: "the version reported by the agent",
+						},
+						"last_contact": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the time the agent last contacted the server (unix timestamp)",
+						},
+						"no_schedule": schema.BoolAttribute{
+							Computed:    true,
+							Description: "whether new tasks are not scheduled on this agent",
+						},
+						"custom_labels": schema.MapAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "custom labels assigned to the agent",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *agentsDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *agentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data agentsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var customLabels map[string]string
+	resp.Diagnostics.Append(data.CustomLabels.ElementsAs(ctx, &customLabels, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agents, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Agent, error) {
+		if data.OrgID.IsNull() {
+			return d.client.WithContext(ctx).AgentList(woodpecker.AgentListOptions{ListOptions: opt})
+		}
+		return d.client.WithContext(ctx).OrgAgentList(data.OrgID.ValueInt64(), woodpecker.AgentListOptions{ListOptions: opt})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't list agents", err.Error())
+		return
+	}
+
+	filtered := make([]*woodpecker.Agent, 0, len(agents))
+	for _, agent := range agents {
+		if hasAgentCustomLabels(agent, customLabels) {
+			filtered = append(filtered, agent)
+		}
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, filtered)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func hasAgentCustomLabels(agent *woodpecker.Agent, labels map[string]string) bool {
+	for k, v := range labels {
+		if agentValue, ok := agent.CustomLabels[k]; !ok || agentValue != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,75 @@
+package internal_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAgentsDataSource(t *testing.T) {
+	t.Parallel()
+
+	org := createOrg(t)
+	label := uuid.NewString()
+	name1 := uuid.NewString()
+	name2 := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_agent" "test_agent" {
+	name = "%s"
+	custom_labels = {
+		"test" = "%s"
+	}
+}
+
+resource "woodpecker_org_agent" "test_agent" {
+	org_id = %d
+	name = "%s"
+}
+
+data "woodpecker_agents" "by_label" {
+	custom_labels = {
+		"test" = woodpecker_agent.test_agent.custom_labels["test"]
+	}
+}
+
+data "woodpecker_agents" "by_org" {
+	org_id = woodpecker_org_agent.test_agent.org_id
+}
+`, name1, label, org.ID, name2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_agents.by_label", "agents.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.woodpecker_agents.by_label",
+						"agents.0.id",
+						"woodpecker_agent.test_agent",
+						"id",
+					),
+					resource.TestCheckResourceAttr("data.woodpecker_agents.by_label", "agents.0.name", name1),
+					resource.TestCheckResourceAttr("data.woodpecker_agents.by_label", "agents.0.custom_labels.test", label),
+					resource.TestCheckResourceAttr("data.woodpecker_agents.by_org", "agents.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.woodpecker_agents.by_org",
+						"agents.0.id",
+						"woodpecker_org_agent.test_agent",
+						"id",
+					),
+					resource.TestCheckResourceAttr("data.woodpecker_agents.by_org", "agents.0.name", name2),
+					resource.TestCheckResourceAttr(
+						"data.woodpecker_agents.by_org",
+						"agents.0.org_id",
+						strconv.FormatInt(org.ID, 10),
+					),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type globalRegistryDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*globalRegistryDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*globalRegistryDataSource)(nil)
+
+func newGlobalRegistryDataSource() datasource.DataSource {
+	return &globalRegistryDataSource{}
+}
+
+func (d *globalRegistryDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_global_registry"
+}
+
+func (d *globalRegistryDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about a global container registry.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the id of the registry",
+			},
+			"address": schema.StringAttribute{
+				Required:    true,
+				Description: "the address of the registry (e.g. docker.io)",
+			},
+			"username": schema.StringAttribute{
+				Computed:    true,
+				Description: "username used for authentication",
+			},
+		},
+	}
+}
+
+func (d *globalRegistryDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *globalRegistryDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data globalRegistryDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry, err := d.client.WithContext(ctx).GlobalRegistry(data.Address.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get registry data", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, registry)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
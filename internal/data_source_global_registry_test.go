@@ -0,0 +1,40 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestGlobalRegistryDataSource(t *testing.T) {
+	t.Parallel()
+
+	address := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_global_registry" "test_registry" {
+	address = "%s"
+	username = "test"
+	password = "test"
+}
+
+data "woodpecker_global_registry" "test_registry" {
+	address = woodpecker_global_registry.test_registry.address
+}
+`, address),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.woodpecker_global_registry.test_registry", "id"),
+					resource.TestCheckResourceAttr("data.woodpecker_global_registry.test_registry", "address", address),
+					resource.TestCheckResourceAttr("data.woodpecker_global_registry.test_registry", "username", "test"),
+				),
+			},
+		},
+	})
+}
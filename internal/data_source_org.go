@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 )
 
 type orgDataSource struct {
@@ -15,6 +17,7 @@ type orgDataSource struct {
 
 var _ datasource.DataSource = (*orgDataSource)(nil)
 var _ datasource.DataSourceWithConfigure = (*orgDataSource)(nil)
+var _ datasource.DataSourceWithConfigValidators = (*orgDataSource)(nil)
 
 func newOrgDataSource() datasource.DataSource {
 	return &orgDataSource{}
@@ -30,24 +33,35 @@ func (d *orgDataSource) Metadata(
 
 func (d *orgDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Use this data source to retrieve information about an organization.",
+		MarkdownDescription: "Use this data source to retrieve information about an organization" +
+			" and the permissions of the current user in it.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "the org's id",
+				Description: "the org's id, exactly one of `id` or `name` must be set",
 			},
 			"forge_id": schema.Int64Attribute{
 				Computed:    true,
 				Description: "the forge's id",
 			},
 			"name": schema.StringAttribute{
-				Required:    true,
-				Description: "the org's name",
+				Optional:    true,
+				Computed:    true,
+				Description: "the org's name, exactly one of `id` or `name` must be set",
 			},
 			"is_user": schema.BoolAttribute{
 				Computed:    true,
 				Description: "whether org is a user",
 			},
+			"admin": schema.BoolAttribute{
+				Computed:    true,
+				Description: "whether the current user is an admin of the org",
+			},
+			"member": schema.BoolAttribute{
+				Computed:    true,
+				Description: "whether the current user is a member of the org",
+			},
 		},
 	}
 }
@@ -77,6 +91,15 @@ func (d *orgDataSource) Configure(
 	d.client = client
 }
 
+func (d *orgDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+		),
+	}
+}
+
 func (d *orgDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data orgModel
 
@@ -85,13 +108,25 @@ func (d *orgDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 
-	org, err := d.client.OrgLookup(data.Name.ValueString())
+	var org *woodpecker.Org
+	var err error
+	if !data.ID.IsNull() {
+		org, err = d.client.WithContext(ctx).Org(data.ID.ValueInt64())
+	} else {
+		org, err = d.client.WithContext(ctx).OrgLookup(data.Name.ValueString())
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get org data", err.Error())
 		return
 	}
 
-	resp.Diagnostics.Append(data.setValues(ctx, org)...)
+	perm, err := d.client.WithContext(ctx).OrgPermissions(org.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get org permissions", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, org, perm)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -0,0 +1,44 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestOrgRegistryDataSource(t *testing.T) {
+	t.Parallel()
+
+	org := createOrg(t)
+	address := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = %d
+	address = "%s"
+	username = "test"
+	password = "test"
+}
+
+data "woodpecker_org_registry" "test_registry" {
+	org_id = woodpecker_org_registry.test_registry.org_id
+	address = woodpecker_org_registry.test_registry.address
+}
+`, org.ID, address),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.woodpecker_org_registry.test_registry", "id"),
+					resource.TestCheckResourceAttrSet("data.woodpecker_org_registry.test_registry", "org_id"),
+					resource.TestCheckResourceAttr("data.woodpecker_org_registry.test_registry", "address", address),
+					resource.TestCheckResourceAttr("data.woodpecker_org_registry.test_registry", "username", "test"),
+				),
+			},
+		},
+	})
+}
@@ -111,7 +111,7 @@ func (d *orgSecretDataSource) Read(
 		return
 	}
 
-	secret, err := d.client.OrgSecret(data.OrgID.ValueInt64(), data.Name.ValueString())
+	secret, err := d.client.WithContext(ctx).OrgSecret(data.OrgID.ValueInt64(), data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get secret data", err.Error())
 		return
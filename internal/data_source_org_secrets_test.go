@@ -0,0 +1,65 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestOrgSecretsDataSource(t *testing.T) {
+	t.Parallel()
+
+	org := createOrg(t)
+	prefix := uuid.NewString()
+	name1 := prefix + "-1"
+	name2 := prefix + "-2"
+	name3 := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_org_secret" "test_secret" {
+	for_each = toset(["%s", "%s", "%s"])
+	org_id = %d
+	name = each.key
+	value = "test123"
+	events = ["%s"]
+}
+
+data "woodpecker_org_secrets" "all" {
+	org_id = %d
+
+	depends_on = [woodpecker_org_secret.test_secret]
+}
+
+data "woodpecker_org_secrets" "prefix" {
+	org_id = %d
+	name_prefix = "%s"
+
+	depends_on = [woodpecker_org_secret.test_secret]
+}
+`, name1, name2, name3, org.ID, woodpecker.EventPush, org.ID, org.ID, prefix),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_org_secrets.all", "secrets.#", "3"),
+					resource.TestCheckResourceAttr("data.woodpecker_org_secrets.prefix", "secrets.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_org_secrets.prefix",
+						"secrets.*",
+						map[string]string{"name": name1},
+					),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_org_secrets.prefix",
+						"secrets.*",
+						map[string]string{"name": name2},
+					),
+				),
+			},
+		},
+	})
+}
@@ -2,6 +2,8 @@ package internal_test
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -10,41 +12,82 @@ import (
 func TestOrgDataSource(t *testing.T) {
 	t.Parallel()
 
-	repo1 := createOrgRepo(t)
-	activateRepo(t, repo1)
-	repo2 := createRepo(t)
-	activateRepo(t, repo2)
-
-	resource.Test(t, resource.TestCase{
-		PreCheck:                 func() { testAccPreCheck(t) },
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
-			{
-				Config: fmt.Sprintf(`
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		repo1 := createOrgRepo(t)
+		activateRepo(t, repo1)
+		repo2 := createRepo(t)
+		activateRepo(t, repo2)
+		org := createOrg(t)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
 data "woodpecker_org" "test_org" {
 	name = "%s"
 }
 `, repo1.Owner.UserName),
-				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "id"),
-					resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "forge_id"),
-					resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "name", repo1.Owner.UserName),
-					resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "is_user", "false"),
-				),
-			},
-			{
-				Config: fmt.Sprintf(`
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "id"),
+						resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "forge_id"),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "name", repo1.Owner.UserName),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "is_user", "false"),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "admin", "true"),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "member", "true"),
+					),
+				},
+				{
+					Config: fmt.Sprintf(`
 data "woodpecker_org" "test_org" {
 	name = "%s"
 }
 `, repo2.Owner.UserName),
-				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "id"),
-					resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "forge_id"),
-					resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "name", repo2.Owner.UserName),
-					resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "is_user", "true"),
-				),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "id"),
+						resource.TestCheckResourceAttrSet("data.woodpecker_org.test_org", "forge_id"),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "name", repo2.Owner.UserName),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "is_user", "true"),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "admin", "true"),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "member", "true"),
+					),
+				},
+				{
+					Config: fmt.Sprintf(`
+data "woodpecker_org" "test_org" {
+	id = %d
+}
+`, org.ID),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "id", strconv.FormatInt(org.ID, 10)),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "name", org.Name),
+						resource.TestCheckResourceAttr("data.woodpecker_org.test_org", "admin", "true"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: id and name are mutually exclusive", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+data "woodpecker_org" "test_org" {
+	id = 1
+	name = "test"
+}
+`,
+					ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+				},
 			},
-		},
+		})
 	})
 }
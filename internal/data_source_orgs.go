@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type orgsDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*orgsDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*orgsDataSource)(nil)
+
+func newOrgsDataSource() datasource.DataSource {
+	return &orgsDataSource{}
+}
+
+func (d *orgsDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_orgs"
+}
+
+func (d *orgsDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about all organizations." +
+			" This data source requires admin privileges.",
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "return only organizations whose name starts with the given prefix",
+			},
+			"orgs": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the list of organizations",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the org's id",
+						},
+						"forge_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the forge's id",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "the org's name",
+						},
+						"is_user": schema.BoolAttribute{
+							Computed:    true,
+							Description: "whether org is a user",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *orgsDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *orgsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data orgsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	orgs, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Org, error) {
+		return d.client.WithContext(ctx).OrgList(woodpecker.OrgListOptions{ListOptions: opt})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't list orgs", err.Error())
+		return
+	}
+
+	if prefix := data.NamePrefix.ValueString(); prefix != "" {
+		orgs = slices.DeleteFunc(orgs, func(org *woodpecker.Org) bool {
+			return !strings.HasPrefix(org.Name, prefix)
+		})
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, orgs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,41 @@
+package internal_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestOrgsDataSource(t *testing.T) {
+	t.Parallel()
+
+	org := createOrg(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "woodpecker_orgs" "all" {}
+
+data "woodpecker_orgs" "prefix" {
+	name_prefix = "%s"
+}
+`, org.Name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_orgs.all",
+						"orgs.*",
+						map[string]string{"id": strconv.FormatInt(org.ID, 10), "name": org.Name},
+					),
+					resource.TestCheckResourceAttr("data.woodpecker_orgs.prefix", "orgs.#", "1"),
+					resource.TestCheckResourceAttr("data.woodpecker_orgs.prefix", "orgs.0.name", org.Name),
+					resource.TestCheckResourceAttr("data.woodpecker_orgs.prefix", "orgs.0.is_user", "false"),
+				),
+			},
+		},
+	})
+}
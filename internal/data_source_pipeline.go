@@ -0,0 +1,264 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type pipelineDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*pipelineDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*pipelineDataSource)(nil)
+
+func newPipelineDataSource() datasource.DataSource {
+	return &pipelineDataSource{}
+}
+
+func (d *pipelineDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_pipeline"
+}
+
+func (d *pipelineDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about a pipeline in a specific repository." +
+			" If `number` isn't set, the latest pipeline is returned.",
+		Attributes: map[string]schema.Attribute{
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+			},
+			"number": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "the number of the pipeline, the latest pipeline is returned if it isn't set",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.ConflictsWith(path.MatchRoot("branch")),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "the branch of the pipeline, if `number` isn't set it's used to find" +
+					" the latest pipeline (defaults to the repository default branch)",
+			},
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the id of the pipeline",
+			},
+			"parent": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the number of the pipeline this pipeline was restarted from",
+			},
+			"event": schema.StringAttribute{
+				Computed:    true,
+				Description: "the event that triggered the pipeline",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "the status of the pipeline",
+			},
+			"commit": schema.StringAttribute{
+				Computed:    true,
+				Description: "the commit the pipeline was run on",
+			},
+			"ref": schema.StringAttribute{
+				Computed:    true,
+				Description: "the git ref the pipeline was run on",
+			},
+			"message": schema.StringAttribute{
+				Computed:    true,
+				Description: "the commit message",
+			},
+			"author": schema.StringAttribute{
+				Computed:    true,
+				Description: "the commit author",
+			},
+			"sender": schema.StringAttribute{
+				Computed:    true,
+				Description: "the user who triggered the pipeline",
+			},
+			"deploy_to": schema.StringAttribute{
+				Computed:    true,
+				Description: "the deployment target, only set for deployment pipelines",
+			},
+			"forge_url": schema.StringAttribute{
+				Computed:    true,
+				Description: "the link to the commit in the forge",
+			},
+			"created": schema.Int64Attribute{
+				Computed:    true,
+				Description: "date the pipeline was created",
+			},
+			"started": schema.Int64Attribute{
+				Computed:    true,
+				Description: "date the pipeline was started",
+			},
+			"finished": schema.Int64Attribute{
+				Computed:    true,
+				Description: "date the pipeline was finished",
+			},
+			"workflows": pipelineWorkflowsDataSourceAttribute(),
+		},
+	}
+}
+
+func (d *pipelineDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *pipelineDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data pipelineDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var pipeline *woodpecker.Pipeline
+	var err error
+	if data.Number.IsNull() {
+		pipeline, err = d.client.WithContext(ctx).PipelineLast(
+			data.RepositoryID.ValueInt64(),
+			woodpecker.PipelineLastOptions{Branch: data.Branch.ValueString()},
+		)
+	} else {
+		pipeline, err = d.client.WithContext(ctx).Pipeline(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't read pipeline", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, pipeline)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func pipelineWorkflowsDataSourceAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Computed:    true,
+		Description: "the workflows of the pipeline",
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.Int64Attribute{
+					Computed:    true,
+					Description: "the id of the workflow",
+				},
+				"pid": schema.Int64Attribute{
+					Computed:    true,
+					Description: "the process id of the workflow within the pipeline",
+				},
+				"name": schema.StringAttribute{
+					Computed:    true,
+					Description: "the name of the workflow",
+				},
+				"state": schema.StringAttribute{
+					Computed:    true,
+					Description: "the state of the workflow",
+				},
+				"error": schema.StringAttribute{
+					Computed:    true,
+					Description: "the error the workflow failed with",
+				},
+				"started": schema.Int64Attribute{
+					Computed:    true,
+					Description: "date the workflow was started",
+				},
+				"stopped": schema.Int64Attribute{
+					Computed:    true,
+					Description: "date the workflow was stopped",
+				},
+				"steps": schema.ListNestedAttribute{
+					Computed:    true,
+					Description: "the steps of the workflow",
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"id": schema.Int64Attribute{
+								Computed:    true,
+								Description: "the id of the step",
+							},
+							"pid": schema.Int64Attribute{
+								Computed:    true,
+								Description: "the process id of the step within the pipeline",
+							},
+							"name": schema.StringAttribute{
+								Computed:    true,
+								Description: "the name of the step",
+							},
+							"type": schema.StringAttribute{
+								Computed:    true,
+								Description: "the type of the step",
+							},
+							"state": schema.StringAttribute{
+								Computed:    true,
+								Description: "the state of the step",
+							},
+							"error": schema.StringAttribute{
+								Computed:    true,
+								Description: "the error the step failed with",
+							},
+							"exit_code": schema.Int64Attribute{
+								Computed:    true,
+								Description: "the exit code of the step",
+							},
+							"started": schema.Int64Attribute{
+								Computed:    true,
+								Description: "date the step was started",
+							},
+							"stopped": schema.Int64Attribute{
+								Computed:    true,
+								Description: "date the step was stopped",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var logEntryTypes = []woodpecker.LogEntryType{
+	woodpecker.LogEntryStdout,
+	woodpecker.LogEntryStderr,
+	woodpecker.LogEntryExitCode,
+	woodpecker.LogEntryMetadata,
+	woodpecker.LogEntryProgress,
+}
+
+// logEntryTypeNames returns the names of the log entry types accepted by the types attribute.
+func logEntryTypeNames() []string {
+	names := make([]string, 0, len(logEntryTypes))
+	for _, t := range logEntryTypes {
+		names = append(names, t.String())
+	}
+	return names
+}
+
+type pipelineStepLogDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*pipelineStepLogDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*pipelineStepLogDataSource)(nil)
+
+func newPipelineStepLogDataSource() datasource.DataSource {
+	return &pipelineStepLogDataSource{}
+}
+
+func (d *pipelineStepLogDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_pipeline_step_log"
+}
+
+func (d *pipelineStepLogDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve the log of a pipeline step.",
+		Attributes: map[string]schema.Attribute{
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+			},
+			"pipeline_number": schema.Int64Attribute{
+				Required:    true,
+				Description: "the number of the pipeline",
+			},
+			"step_name": schema.StringAttribute{
+				Required:    true,
+				Description: "the name of the step",
+			},
+			"workflow_name": schema.StringAttribute{
+				Optional: true,
+				Description: "the name of the workflow the step belongs to," +
+					" required if multiple workflows have a step with the same name",
+			},
+			"types": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "types of log entries to return (stdout, stderr, exit_code, metadata, progress)," +
+					" defaults to stdout and stderr",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(
+						stringvalidator.OneOf(logEntryTypeNames()...),
+					),
+				},
+			},
+			"tail": schema.Int64Attribute{
+				Optional:    true,
+				Description: "return only the last n lines of the log",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"step_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the id of the step",
+			},
+			"state": schema.StringAttribute{
+				Computed:    true,
+				Description: "the state of the step",
+			},
+			"exit_code": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the exit code of the step",
+			},
+			"log": schema.StringAttribute{
+				Computed:    true,
+				Description: "the decoded log of the step",
+			},
+		},
+	}
+}
+
+func (d *pipelineStepLogDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *pipelineStepLogDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data pipelineStepLogDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repoID := data.RepositoryID.ValueInt64()
+	number := data.PipelineNumber.ValueInt64()
+
+	pipeline, err := d.client.WithContext(ctx).Pipeline(repoID, number)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't read pipeline", err.Error())
+		return
+	}
+
+	step, err := findPipelineStep(pipeline, data.WorkflowName.ValueString(), data.StepName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't find step", err.Error())
+		return
+	}
+
+	entries, err := d.client.WithContext(ctx).StepLogEntries(repoID, number, step.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't read step log", err.Error())
+		return
+	}
+
+	opts, diags := data.toWoodpeckerLogDecodeOptions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.setValues(step, woodpecker.DecodeLogEntries(entries, opts))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findPipelineStep returns the step with the given name.
+// If workflowName is empty, the step is looked up in all workflows and must be unique.
+func findPipelineStep(pipeline *woodpecker.Pipeline, workflowName, stepName string) (*woodpecker.Step, error) {
+	var found *woodpecker.Step
+	for _, workflow := range pipeline.Workflows {
+		if workflowName != "" && workflow.Name != workflowName {
+			continue
+		}
+		for _, step := range workflow.Children {
+			if step.Name != stepName {
+				continue
+			}
+			if found != nil {
+				return nil, fmt.Errorf("multiple workflows have a step named %q, set workflow_name", stepName)
+			}
+			found = step
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("step %q not found in pipeline #%d", stepName, pipeline.Number)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,112 @@
+package internal_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestPipelineStepLogDataSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		pipeline := runPipeline(
+			t,
+			repo,
+			giteaRepo.DefaultBranch,
+			woodpecker.StatusSuccess,
+			woodpecker.LogEntry{Data: []byte(`+ echo "hello"`), Type: woodpecker.LogEntryStdout},
+			woodpecker.LogEntry{Data: []byte("warning: low disk space"), Type: woodpecker.LogEntryStderr},
+			woodpecker.LogEntry{Data: []byte("héllo wörld"), Type: woodpecker.LogEntryStdout},
+			woodpecker.LogEntry{Data: []byte("50%"), Type: woodpecker.LogEntryProgress},
+			woodpecker.LogEntry{Data: []byte("\x1b[32mdone\x1b[0m"), Type: woodpecker.LogEntryStdout},
+			woodpecker.LogEntry{Data: []byte("0"), Type: woodpecker.LogEntryExitCode},
+		)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+data "woodpecker_pipeline_step_log" "all" {
+	repository_id = %d
+	pipeline_number = %d
+	step_name = "build"
+}
+
+data "woodpecker_pipeline_step_log" "tail" {
+	repository_id = %d
+	pipeline_number = %d
+	workflow_name = "woodpecker"
+	step_name = "build"
+	types = ["stdout"]
+	tail = 2
+}
+
+data "woodpecker_pipeline_step_log" "types" {
+	repository_id = %d
+	pipeline_number = %d
+	step_name = "build"
+	types = ["stderr", "exit_code"]
+}
+`, repo.ID, pipeline.Number, repo.ID, pipeline.Number, repo.ID, pipeline.Number),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("data.woodpecker_pipeline_step_log.all", "step_id"),
+						resource.TestCheckResourceAttr("data.woodpecker_pipeline_step_log.all", "state", "success"),
+						resource.TestCheckResourceAttr("data.woodpecker_pipeline_step_log.all", "exit_code", "0"),
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline_step_log.all",
+							"log",
+							"+ echo \"hello\"\nwarning: low disk space\nhéllo wörld\n\x1b[32mdone\x1b[0m",
+						),
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline_step_log.tail",
+							"log",
+							"héllo wörld\n\x1b[32mdone\x1b[0m",
+						),
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline_step_log.types",
+							"log",
+							"warning: low disk space\n0",
+						),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: step not found", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		pipeline := createPipeline(t, repo, giteaRepo.DefaultBranch)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+data "woodpecker_pipeline_step_log" "test" {
+	repository_id = %d
+	pipeline_number = %d
+	step_name = "unknown"
+}
+`, repo.ID, pipeline.Number),
+					ExpectError: regexp.MustCompile(`Couldn't find step`),
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,118 @@
+package internal_test
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestPipelineDataSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		pipeline1 := createPipeline(t, repo, giteaRepo.DefaultBranch)
+		pipeline2 := createPipeline(t, repo, giteaRepo.DefaultBranch)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+data "woodpecker_pipeline" "test_pipeline_number" {
+	repository_id = %d
+	number = %d
+}
+
+data "woodpecker_pipeline" "test_pipeline_latest" {
+	repository_id = %d
+	branch = "%s"
+}
+`, repo.ID, pipeline1.Number, repo.ID, giteaRepo.DefaultBranch),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline.test_pipeline_number",
+							"id",
+							strconv.FormatInt(pipeline1.ID, 10),
+						),
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline.test_pipeline_number",
+							"number",
+							strconv.FormatInt(pipeline1.Number, 10),
+						),
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline.test_pipeline_number",
+							"branch",
+							giteaRepo.DefaultBranch,
+						),
+						resource.TestCheckResourceAttr("data.woodpecker_pipeline.test_pipeline_number", "event", "manual"),
+						resource.TestCheckResourceAttr("data.woodpecker_pipeline.test_pipeline_number", "commit", pipeline1.Commit),
+						resource.TestCheckResourceAttrSet("data.woodpecker_pipeline.test_pipeline_number", "status"),
+						resource.TestCheckResourceAttrSet("data.woodpecker_pipeline.test_pipeline_number", "created"),
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline.test_pipeline_latest",
+							"number",
+							strconv.FormatInt(pipeline2.Number, 10),
+						),
+						resource.TestCheckResourceAttr(
+							"data.woodpecker_pipeline.test_pipeline_latest",
+							"id",
+							strconv.FormatInt(pipeline2.ID, 10),
+						),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: number and branch are mutually exclusive", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+data "woodpecker_pipeline" "test_pipeline" {
+	repository_id = 1
+	number = 1
+	branch = "main"
+}
+`,
+					ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: pipeline not found", func(t *testing.T) {
+		t.Parallel()
+
+		repo := activateRepo(t, createRepo(t))
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+data "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	number = 1
+}
+`, repo.ID),
+					ExpectError: regexp.MustCompile(`Couldn't read pipeline`),
+				},
+			},
+		})
+	})
+}
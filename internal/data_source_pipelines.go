@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const pipelinesDefaultLimit = 20
+
+type pipelinesDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*pipelinesDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*pipelinesDataSource)(nil)
+
+func newPipelinesDataSource() datasource.DataSource {
+	return &pipelinesDataSource{}
+}
+
+func (d *pipelinesDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_pipelines"
+}
+
+func (d *pipelinesDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about the most recent pipelines" +
+			" in a specific repository. Pipelines are sorted from the newest to the oldest.",
+		Attributes: map[string]schema.Attribute{
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+			},
+			"branch": schema.StringAttribute{
+				Optional:    true,
+				Description: "return only pipelines run on the given branch",
+			},
+			"events": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "return only pipelines triggered by one of the given events " +
+					"(push, tag, pull_request, pull_request_closed, deployment, cron, manual, release)",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(
+						stringvalidator.OneOf(
+							woodpecker.EventPush,
+							woodpecker.EventTag,
+							woodpecker.EventPull,
+							woodpecker.EventPullClosed,
+							woodpecker.EventDeploy,
+							woodpecker.EventCron,
+							woodpecker.EventManual,
+							woodpecker.EventRelease,
+						),
+					),
+				},
+			},
+			"status": schema.StringAttribute{
+				Optional:    true,
+				Description: "return only pipelines with the given status",
+			},
+			"limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("the maximum number of pipelines to return (default: %d)", pipelinesDefaultLimit),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"pipelines": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the list of pipelines",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the id of the pipeline",
+						},
+						"number": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the number of the pipeline",
+						},
+						"parent": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the number of the pipeline this pipeline was restarted from",
+						},
+						"event": schema.StringAttribute{
+							Computed:    true,
+							Description: "the event that triggered the pipeline",
+						},
+						"status": schema.StringAttribute{
+							Computed:    true,
+							Description: "the status of the pipeline",
+						},
+						"branch": schema.StringAttribute{
+							Computed:    true,
+							Description: "the branch the pipeline was run on",
+						},
+						"commit": schema.StringAttribute{
+							Computed:    true,
+							Description: "the commit the pipeline was run on",
+						},
+						"ref": schema.StringAttribute{
+							Computed:    true,
+							Description: "the git ref the pipeline was run on",
+						},
+						"message": schema.StringAttribute{
+							Computed:    true,
+							Description: "the commit message",
+						},
+						"author": schema.StringAttribute{
+							Computed:    true,
+							Description: "the commit author",
+						},
+						"sender": schema.StringAttribute{
+							Computed:    true,
+							Description: "the user who triggered the pipeline",
+						},
+						"deploy_to": schema.StringAttribute{
+							Computed:    true,
+							Description: "the deployment target, only set for deployment pipelines",
+						},
+						"forge_url": schema.StringAttribute{
+							Computed:    true,
+							Description: "the link to the commit in the forge",
+						},
+						"created": schema.Int64Attribute{
+							Computed:    true,
+							Description: "date the pipeline was created",
+						},
+						"started": schema.Int64Attribute{
+							Computed:    true,
+							Description: "date the pipeline was started",
+						},
+						"finished": schema.Int64Attribute{
+							Computed:    true,
+							Description: "date the pipeline was finished",
+						},
+						"workflows": pipelineWorkflowsDataSourceAttribute(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *pipelinesDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *pipelinesDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data pipelinesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts, diags := data.toWoodpeckerListOptions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repoID := data.RepositoryID.ValueInt64()
+	limit := data.limit()
+	client := d.client.WithContext(ctx)
+
+	pipelines := make([]*woodpecker.Pipeline, 0, limit)
+	for pipeline, err := range woodpecker.Paginate(func(opt woodpecker.ListOptions) ([]*woodpecker.Pipeline, error) {
+		opts.ListOptions = opt
+		return client.PipelineList(repoID, opts)
+	}) {
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't list pipelines", err.Error())
+			return
+		}
+
+		// the list endpoint doesn't return workflows
+		pipeline, err = client.Pipeline(repoID, pipeline.Number)
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't read pipeline", err.Error())
+			return
+		}
+
+		pipelines = append(pipelines, pipeline)
+		if len(pipelines) == limit {
+			break
+		}
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, pipelines)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
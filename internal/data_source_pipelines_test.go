@@ -0,0 +1,67 @@
+package internal_test
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestPipelinesDataSource(t *testing.T) {
+	t.Parallel()
+
+	giteaRepo := createRepo(t)
+	createPipelineConfig(t, giteaRepo)
+	repo := activateRepo(t, giteaRepo)
+	pipeline1 := createPipeline(t, repo, giteaRepo.DefaultBranch)
+	pipeline2 := createPipeline(t, repo, giteaRepo.DefaultBranch)
+	pipeline3 := createPipeline(t, repo, giteaRepo.DefaultBranch)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "woodpecker_pipelines" "all" {
+	repository_id = %d
+}
+
+data "woodpecker_pipelines" "limit" {
+	repository_id = %d
+	branch = "%s"
+	events = ["manual"]
+	limit = 2
+}
+
+data "woodpecker_pipelines" "push" {
+	repository_id = %d
+	events = ["push"]
+}
+`, repo.ID, repo.ID, giteaRepo.DefaultBranch, repo.ID),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_pipelines.all", "pipelines.#", "3"),
+					resource.TestCheckResourceAttr(
+						"data.woodpecker_pipelines.all",
+						"pipelines.2.number",
+						strconv.FormatInt(pipeline1.Number, 10),
+					),
+					resource.TestCheckResourceAttr("data.woodpecker_pipelines.limit", "pipelines.#", "2"),
+					resource.TestCheckResourceAttr(
+						"data.woodpecker_pipelines.limit",
+						"pipelines.0.number",
+						strconv.FormatInt(pipeline3.Number, 10),
+					),
+					resource.TestCheckResourceAttr(
+						"data.woodpecker_pipelines.limit",
+						"pipelines.1.number",
+						strconv.FormatInt(pipeline2.Number, 10),
+					),
+					resource.TestCheckResourceAttrSet("data.woodpecker_pipelines.limit", "pipelines.0.workflows.#"),
+					resource.TestCheckResourceAttr("data.woodpecker_pipelines.push", "pipelines.#", "0"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,167 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type repositoriesDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*repositoriesDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*repositoriesDataSource)(nil)
+
+func newRepositoriesDataSource() datasource.DataSource {
+	return &repositoriesDataSource{}
+}
+
+func (d *repositoriesDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_repositories"
+}
+
+func (d *repositoriesDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about all repositories" +
+			" the authenticated user has access to.",
+		Attributes: map[string]schema.Attribute{
+			"name_prefix": schema.StringAttribute{
+				Optional: true,
+				Description: "return only repositories whose full name starts with the given prefix" +
+					" (e.g. \"owner/\" returns all repositories of the given owner)",
+			},
+			"active_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: "return only repositories that are activated in Woodpecker",
+			},
+			"repositories": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the list of repositories",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the repository's id",
+						},
+						"forge_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the forge's id",
+						},
+						"forge_remote_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "the unique identifier for the repository on the forge",
+						},
+						"owner": schema.StringAttribute{
+							Computed:    true,
+							Description: "the owner of the repository",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "the name of the repository",
+						},
+						"full_name": schema.StringAttribute{
+							Computed:    true,
+							Description: "the full name of the repository (format: owner/reponame)",
+						},
+						"forge_url": schema.StringAttribute{
+							Computed:    true,
+							Description: "the URL of the repository on the forge",
+						},
+						"clone_url": schema.StringAttribute{
+							Computed:    true,
+							Description: "the URL to clone repository",
+						},
+						"default_branch": schema.StringAttribute{
+							Computed:    true,
+							Description: "the name of the default branch",
+						},
+						"visibility": schema.StringAttribute{
+							Computed:    true,
+							Description: "project visibility",
+						},
+						"is_private": schema.BoolAttribute{
+							Computed:    true,
+							Description: "whether the repo (SCM) is private",
+						},
+						"is_active": schema.BoolAttribute{
+							Computed:    true,
+							Description: "whether the repo is active",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *repositoriesDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *repositoriesDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data repositoriesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the API returns only active repositories unless all is set
+	repos, err := d.client.WithContext(ctx).RepoList(woodpecker.RepoListOptions{All: !data.ActiveOnly.ValueBool()})
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't list repositories", err.Error())
+		return
+	}
+
+	if prefix := data.NamePrefix.ValueString(); prefix != "" {
+		repos = slices.DeleteFunc(repos, func(repo *woodpecker.Repo) bool {
+			return !strings.HasPrefix(repo.FullName, prefix)
+		})
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, repos)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
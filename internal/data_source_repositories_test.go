@@ -0,0 +1,58 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRepositoriesDataSource(t *testing.T) {
+	t.Parallel()
+
+	activeRepo := createRepo(t)
+	activateRepo(t, activeRepo)
+	inactiveRepo := createRepo(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "woodpecker_repositories" "active" {
+	name_prefix = "%s"
+	active_only = true
+}
+
+data "woodpecker_repositories" "inactive" {
+	name_prefix = "%s"
+}
+
+data "woodpecker_repositories" "inactive_active_only" {
+	name_prefix = "%s"
+	active_only = true
+}
+`, activeRepo.FullName, inactiveRepo.FullName, inactiveRepo.FullName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_repositories.active", "repositories.#", "1"),
+					resource.TestCheckResourceAttrSet("data.woodpecker_repositories.active", "repositories.0.id"),
+					resource.TestCheckResourceAttr(
+						"data.woodpecker_repositories.active",
+						"repositories.0.full_name",
+						activeRepo.FullName,
+					),
+					resource.TestCheckResourceAttr("data.woodpecker_repositories.active", "repositories.0.is_active", "true"),
+					resource.TestCheckResourceAttr("data.woodpecker_repositories.inactive", "repositories.#", "1"),
+					resource.TestCheckResourceAttr(
+						"data.woodpecker_repositories.inactive",
+						"repositories.0.full_name",
+						inactiveRepo.FullName,
+					),
+					resource.TestCheckResourceAttr("data.woodpecker_repositories.inactive", "repositories.0.is_active", "false"),
+					resource.TestCheckResourceAttr("data.woodpecker_repositories.inactive_active_only", "repositories.#", "0"),
+				),
+			},
+		},
+	})
+}
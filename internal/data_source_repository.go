@@ -165,6 +165,27 @@ func (d *repositoryDataSource) Schema(
 				Description: "Plugins that get access to netrc credentials that can " +
 					"be used to clone repositories from the forge or push them into the forge.",
 			},
+			"config_extension_endpoint": schema.StringAttribute{
+				Computed: true,
+				Description: "the URL of the config extension that is asked for the pipeline configuration" +
+					" instead of the forge",
+			},
+			"registry_extension_endpoint": schema.StringAttribute{
+				Computed:    true,
+				Description: "the URL of the extension that provides registries for pipelines of the repository",
+			},
+			"secret_extension_endpoint": schema.StringAttribute{
+				Computed:    true,
+				Description: "the URL of the extension that provides secrets for pipelines of the repository",
+			},
+			"max_pipeline_age": schema.Int64Attribute{
+				Computed:    true,
+				Description: "pipelines older than this are deleted (in days, 0 means the server default)",
+			},
+			"log_expiry": schema.Int64Attribute{
+				Computed:    true,
+				Description: "logs of pipelines older than this are deleted (in days, 0 means the server default)",
+			},
 		},
 	}
 }
@@ -202,7 +223,7 @@ func (d *repositoryDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
-	repo, err := d.client.RepoLookup(data.FullName.ValueString())
+	repo, err := d.client.WithContext(ctx).RepoLookup(data.FullName.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get repository data", err.Error())
 		return
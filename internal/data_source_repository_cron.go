@@ -105,7 +105,7 @@ func (d *repositoryCronDataSource) Read(
 		return
 	}
 
-	cron, err := d.client.CronGet(data.RepositoryID.ValueInt64(), data.ID.ValueInt64())
+	cron, err := d.client.WithContext(ctx).CronGet(data.RepositoryID.ValueInt64(), data.ID.ValueInt64())
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get cron job data", err.Error())
 		return
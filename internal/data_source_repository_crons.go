@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type repositoryCronsDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*repositoryCronsDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*repositoryCronsDataSource)(nil)
+
+func newRepositoryCronsDataSource() datasource.DataSource {
+	return &repositoryCronsDataSource{}
+}
+
+func (d *repositoryCronsDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_repository_crons"
+}
+
+func (d *repositoryCronsDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about all cron jobs in a specific repository.",
+		Attributes: map[string]schema.Attribute{
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+			},
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "return only cron jobs whose name starts with the given prefix",
+			},
+			"crons": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the list of cron jobs",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the cron job's id",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "the name of the cron job",
+						},
+						"creator_id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the ID of the user who created the cron job",
+						},
+						"schedule": schema.StringAttribute{
+							Computed:    true,
+							Description: "cron expression",
+						},
+						"created_at": schema.Int64Attribute{
+							Computed:    true,
+							Description: "date the cron job was created",
+						},
+						"branch": schema.StringAttribute{
+							Computed:    true,
+							Description: "the name of the branch (uses default branch if empty)",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *repositoryCronsDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *repositoryCronsDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data repositoryCronsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	crons, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Cron, error) {
+		return d.client.WithContext(ctx).CronList(
+			data.RepositoryID.ValueInt64(),
+			woodpecker.CronListOptions{ListOptions: opt},
+		)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't list cron jobs", err.Error())
+		return
+	}
+
+	if prefix := data.NamePrefix.ValueString(); prefix != "" {
+		crons = slices.DeleteFunc(crons, func(cron *woodpecker.Cron) bool {
+			return !strings.HasPrefix(cron.Name, prefix)
+		})
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, crons)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
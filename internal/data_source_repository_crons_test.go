@@ -0,0 +1,67 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRepositoryCronsDataSource(t *testing.T) {
+	t.Parallel()
+
+	repo := createRepo(t)
+	prefix := uuid.NewString()
+	name1 := prefix + "-1"
+	name2 := prefix + "-2"
+	name3 := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+}
+
+resource "woodpecker_repository_cron" "test_cron" {
+	for_each = toset(["%s", "%s", "%s"])
+	repository_id = woodpecker_repository.test_repo.id
+	name = each.key
+	schedule = "@daily"
+}
+
+data "woodpecker_repository_crons" "all" {
+	repository_id = woodpecker_repository.test_repo.id
+
+	depends_on = [woodpecker_repository_cron.test_cron]
+}
+
+data "woodpecker_repository_crons" "prefix" {
+	repository_id = woodpecker_repository.test_repo.id
+	name_prefix = "%s"
+
+	depends_on = [woodpecker_repository_cron.test_cron]
+}
+`, repo.FullName, name1, name2, name3, prefix),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_repository_crons.all", "crons.#", "3"),
+					resource.TestCheckResourceAttr("data.woodpecker_repository_crons.prefix", "crons.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_repository_crons.prefix",
+						"crons.*",
+						map[string]string{"name": name1, "schedule": "@daily"},
+					),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_repository_crons.prefix",
+						"crons.*",
+						map[string]string{"name": name2, "schedule": "@daily"},
+					),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type repositoryPermissionsDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*repositoryPermissionsDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*repositoryPermissionsDataSource)(nil)
+
+func newRepositoryPermissionsDataSource() datasource.DataSource {
+	return &repositoryPermissionsDataSource{}
+}
+
+func (d *repositoryPermissionsDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_repository_permissions"
+}
+
+func (d *repositoryPermissionsDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve the permissions of the current user in a repository." +
+			" It can be used in preconditions to check that the user can manage the repository" +
+			" before changing its secrets or settings.",
+		Attributes: map[string]schema.Attribute{
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+			},
+			"pull": schema.BoolAttribute{
+				Computed:    true,
+				Description: "whether the user can pull the repository",
+			},
+			"push": schema.BoolAttribute{
+				Computed:    true,
+				Description: "whether the user can push to the repository",
+			},
+			"admin": schema.BoolAttribute{
+				Computed:    true,
+				Description: "whether the user is an admin of the repository",
+			},
+			"synced": schema.Int64Attribute{
+				Computed:    true,
+				Description: "date the permissions were last synced with the forge",
+			},
+		},
+	}
+}
+
+func (d *repositoryPermissionsDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *repositoryPermissionsDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data repositoryPermissionsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	perm, err := d.client.WithContext(ctx).RepoPermissions(data.RepositoryID.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get repository permissions", err.Error())
+		return
+	}
+
+	data.setValues(perm)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,58 @@
+package internal_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRepositoryPermissionsDataSource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		repo := activateRepo(t, createRepo(t))
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+data "woodpecker_repository_permissions" "test_perms" {
+	repository_id = %d
+}
+`, repo.ID),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.woodpecker_repository_permissions.test_perms", "pull", "true"),
+						resource.TestCheckResourceAttr("data.woodpecker_repository_permissions.test_perms", "push", "true"),
+						resource.TestCheckResourceAttr("data.woodpecker_repository_permissions.test_perms", "admin", "true"),
+						resource.TestCheckResourceAttrSet("data.woodpecker_repository_permissions.test_perms", "synced"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: repository not found", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+data "woodpecker_repository_permissions" "test_perms" {
+	repository_id = 999999
+}
+`,
+					ExpectError: regexp.MustCompile(`Couldn't get repository permissions`),
+				},
+			},
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+)
+
+type repositoryRegistriesDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*repositoryRegistriesDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*repositoryRegistriesDataSource)(nil)
+
+func newRepositoryRegistriesDataSource() datasource.DataSource {
+	return &repositoryRegistriesDataSource{}
+}
+
+func (d *repositoryRegistriesDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_repository_registries"
+}
+
+func (d *repositoryRegistriesDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about all container registries" +
+			" in a specific repository.",
+		Attributes: map[string]schema.Attribute{
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+			},
+			"address_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "return only registries whose address starts with the given prefix",
+			},
+			"registries": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the list of registries",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the id of the registry",
+						},
+						"address": schema.StringAttribute{
+							Computed:    true,
+							Description: "the address of the registry (e.g. docker.io)",
+						},
+						"username": schema.StringAttribute{
+							Computed:    true,
+							Description: "username used for authentication",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *repositoryRegistriesDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *repositoryRegistriesDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data repositoryRegistriesDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registries, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Registry, error) {
+		return d.client.WithContext(ctx).RegistryList(
+			data.RepositoryID.ValueInt64(),
+			woodpecker.RegistryListOptions{ListOptions: opt},
+		)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't list registries", err.Error())
+		return
+	}
+
+	if prefix := data.AddressPrefix.ValueString(); prefix != "" {
+		registries = slices.DeleteFunc(registries, func(registry *woodpecker.Registry) bool {
+			return !strings.HasPrefix(registry.Address, prefix)
+		})
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, registries)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
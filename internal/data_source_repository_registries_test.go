@@ -0,0 +1,68 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRepositoryRegistriesDataSource(t *testing.T) {
+	t.Parallel()
+
+	repo := createRepo(t)
+	prefix := uuid.NewString()
+	address1 := prefix + "-1.localhost"
+	address2 := prefix + "-2.localhost"
+	address3 := uuid.NewString() + ".localhost"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+}
+
+resource "woodpecker_repository_registry" "test_registry" {
+	for_each = toset(["%s", "%s", "%s"])
+	repository_id = woodpecker_repository.test_repo.id
+	address = each.key
+	username = "test"
+	password = "test"
+}
+
+data "woodpecker_repository_registries" "all" {
+	repository_id = woodpecker_repository.test_repo.id
+
+	depends_on = [woodpecker_repository_registry.test_registry]
+}
+
+data "woodpecker_repository_registries" "prefix" {
+	repository_id = woodpecker_repository.test_repo.id
+	address_prefix = "%s"
+
+	depends_on = [woodpecker_repository_registry.test_registry]
+}
+`, repo.FullName, address1, address2, address3, prefix),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_repository_registries.all", "registries.#", "3"),
+					resource.TestCheckResourceAttr("data.woodpecker_repository_registries.prefix", "registries.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_repository_registries.prefix",
+						"registries.*",
+						map[string]string{"address": address1, "username": "test"},
+					),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_repository_registries.prefix",
+						"registries.*",
+						map[string]string{"address": address2, "username": "test"},
+					),
+				),
+			},
+		},
+	})
+}
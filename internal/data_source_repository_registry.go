@@ -94,7 +94,7 @@ func (d *repositoryRegistryDataSource) Read(
 		return
 	}
 
-	registry, err := d.client.Registry(data.RepositoryID.ValueInt64(), data.Address.ValueString())
+	registry, err := d.client.WithContext(ctx).Registry(data.RepositoryID.ValueInt64(), data.Address.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get registry data", err.Error())
 		return
@@ -111,7 +111,7 @@ func (d *repositorySecretDataSource) Read(
 		return
 	}
 
-	secret, err := d.client.Secret(data.RepositoryID.ValueInt64(), data.Name.ValueString())
+	secret, err := d.client.WithContext(ctx).Secret(data.RepositoryID.ValueInt64(), data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get secret data", err.Error())
 		return
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type repositorySecretsDataSource struct {
+	client woodpecker.Client
+}
+
+var _ datasource.DataSource = (*repositorySecretsDataSource)(nil)
+var _ datasource.DataSourceWithConfigure = (*repositorySecretsDataSource)(nil)
+
+func newRepositorySecretsDataSource() datasource.DataSource {
+	return &repositorySecretsDataSource{}
+}
+
+func (d *repositorySecretsDataSource) Metadata(
+	_ context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_repository_secrets"
+}
+
+func (d *repositorySecretsDataSource) Schema(
+	_ context.Context,
+	_ datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Use this data source to retrieve information about all secrets in a specific repository.",
+		Attributes: map[string]schema.Attribute{
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+			},
+			"name_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "return only secrets whose name starts with the given prefix",
+			},
+			"secrets": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the list of secrets",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the secret's id",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "the name of the secret",
+						},
+						"images": schema.SetAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "list of Docker images where this secret is available",
+						},
+						"events": schema.SetAttribute{
+							ElementType: types.StringType,
+							Computed:    true,
+							Description: "events for which the secret is available",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *repositorySecretsDataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	resp *datasource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *repositorySecretsDataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var data repositorySecretsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secrets, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Secret, error) {
+		return d.client.WithContext(ctx).SecretList(
+			data.RepositoryID.ValueInt64(),
+			woodpecker.SecretListOptions{ListOptions: opt},
+		)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't list secrets", err.Error())
+		return
+	}
+
+	if prefix := data.NamePrefix.ValueString(); prefix != "" {
+		secrets = slices.DeleteFunc(secrets, func(secret *woodpecker.Secret) bool {
+			return !strings.HasPrefix(secret.Name, prefix)
+		})
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, secrets)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,69 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRepositorySecretsDataSource(t *testing.T) {
+	t.Parallel()
+
+	repo := createRepo(t)
+	prefix := uuid.NewString()
+	name1 := prefix + "-1"
+	name2 := prefix + "-2"
+	name3 := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+}
+
+resource "woodpecker_repository_secret" "test_secret" {
+	for_each = toset(["%s", "%s", "%s"])
+	repository_id = woodpecker_repository.test_repo.id
+	name = each.key
+	value = "test123"
+	events = ["%s"]
+}
+
+data "woodpecker_repository_secrets" "all" {
+	repository_id = woodpecker_repository.test_repo.id
+
+	depends_on = [woodpecker_repository_secret.test_secret]
+}
+
+data "woodpecker_repository_secrets" "prefix" {
+	repository_id = woodpecker_repository.test_repo.id
+	name_prefix = "%s"
+
+	depends_on = [woodpecker_repository_secret.test_secret]
+}
+`, repo.FullName, name1, name2, name3, woodpecker.EventPush, prefix),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_repository_secrets.all", "secrets.#", "3"),
+					resource.TestCheckResourceAttr("data.woodpecker_repository_secrets.prefix", "secrets.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_repository_secrets.prefix",
+						"secrets.*",
+						map[string]string{"name": name1},
+					),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_repository_secrets.prefix",
+						"secrets.*",
+						map[string]string{"name": name2},
+					),
+				),
+			},
+		},
+	})
+}
@@ -89,7 +89,7 @@ func (d *secretDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	secret, err := d.client.GlobalSecret(data.Name.ValueString())
+	secret, err := d.client.WithContext(ctx).GlobalSecret(data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get secret data", err.Error())
 		return
@@ -0,0 +1,54 @@
+package internal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestSecretsDataSource(t *testing.T) {
+	t.Parallel()
+
+	prefix := uuid.NewString()
+	name1 := prefix + "-1"
+	name2 := prefix + "-2"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "woodpecker_secret" "test_secret" {
+	for_each = toset(["%s", "%s", "%s"])
+	name = each.key
+	value = "test123"
+	events = ["push"]
+}
+
+data "woodpecker_secrets" "prefix" {
+	name_prefix = "%s"
+
+	depends_on = [woodpecker_secret.test_secret]
+}
+`, name1, name2, uuid.NewString(), prefix),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.woodpecker_secrets.prefix", "secrets.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_secrets.prefix",
+						"secrets.*",
+						map[string]string{"name": name1},
+					),
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.woodpecker_secrets.prefix",
+						"secrets.*",
+						map[string]string{"name": name2},
+					),
+					resource.TestCheckTypeSetElemAttr("data.woodpecker_secrets.prefix", "secrets.0.events.*", "push"),
+				),
+			},
+		},
+	})
+}
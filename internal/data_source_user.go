@@ -97,9 +97,9 @@ func (d *userDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	var user *woodpecker.User
 	var err error
 	if login := data.Login.ValueString(); login != "" {
-		user, err = d.client.User(login)
+		user, err = d.client.WithContext(ctx).User(login)
 	} else {
-		user, err = d.client.Self()
+		user, err = d.client.WithContext(ctx).Self()
 	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get user data", err.Error())
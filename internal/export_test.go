@@ -0,0 +1,17 @@
+package internal
+
+import (
+	"net/http"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// NewLazyClient exports newLazyClient for tests, the returned client doesn't retry failed requests.
+func NewLazyClient(server, token string) woodpecker.Client {
+	return newLazyClient(providerConfig{
+		Server:     types.StringValue(server),
+		Token:      types.StringValue(token),
+		httpClient: &http.Client{},
+	})
+}
@@ -20,6 +20,7 @@ import (
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/google/uuid"
 	"github.com/ory/dockertest/v3"
@@ -30,6 +31,8 @@ import (
 var (
 	giteaClient      *gitea.Client
 	woodpeckerClient woodpecker.Client
+	// fakeServer is set when tests run against the in-memory fake of the Woodpecker API.
+	fakeServer *woodpeckertest.Server
 )
 
 func TestMain(m *testing.M) {
@@ -37,6 +40,10 @@ func TestMain(m *testing.M) {
 }
 
 func testMainNoExit(m *testing.M) int {
+	if os.Getenv("WOODPECKER_FAKE_SERVER") == "true" {
+		return testMainFakeServer(m)
+	}
+
 	pool := newDockertestPool()
 
 	network := newDockerNetwork(pool)
@@ -72,6 +79,22 @@ func testMainNoExit(m *testing.M) int {
 	return m.Run()
 }
 
+// testMainFakeServer runs tests against an in-memory fake of the Woodpecker API,
+// it doesn't require Docker or network access.
+func testMainFakeServer(m *testing.M) int {
+	fakeServer = woodpeckertest.NewServer()
+	defer fakeServer.Close()
+
+	woodpeckerClient = fakeServer.Client()
+
+	// set required envs
+	_ = os.Setenv("TF_ACC", "1")
+	_ = os.Setenv("WOODPECKER_SERVER", fakeServer.URL)
+	_ = os.Setenv("WOODPECKER_TOKEN", woodpeckertest.Token)
+
+	return m.Run()
+}
+
 func newDockertestPool() *dockertest.Pool {
 	pool, err := dockertest.NewPool("")
 	if err != nil {
@@ -0,0 +1,506 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+// lazyClient is passed to resources and data sources instead of the Woodpecker client.
+// The client is created and the server is checked (token, version) on the first call,
+// so the provider can be configured before the server is available.
+//
+// Every method goes through get, which creates the client if needed. If the client couldn't be created,
+// the methods return the setup error. Setup errors that may go away on their own (network errors,
+// 429 and 5xx responses, canceled contexts) aren't cached, the next call tries to create the client again.
+type lazyClient struct {
+	state *lazyClientState
+	// ctx is used by the client, it's set by WithContext
+	ctx context.Context
+}
+
+// lazyClientState is shared by the copies of lazyClient returned by WithContext.
+type lazyClientState struct {
+	config providerConfig
+
+	mu     sync.Mutex
+	client woodpecker.Client
+	err    error
+	// setters hold the Set* calls made before the client was created, they're applied once it's created
+	setters []func(woodpecker.Client)
+}
+
+var _ woodpecker.Client = (*lazyClient)(nil)
+
+func newLazyClient(config providerConfig) *lazyClient {
+	return &lazyClient{
+		state: &lazyClientState{config: config},
+		ctx:   context.Background(),
+	}
+}
+
+// get returns the Woodpecker client that uses the context of the lazyClient, the client is created on the first call.
+// If the client couldn't be created, a client that returns the error from every call without sending it is returned.
+func (c *lazyClient) get() woodpecker.Client {
+	s := c.state
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return woodpecker.NewErrClient(s.err)
+	}
+
+	if s.client == nil {
+		client, err := s.newClient(c.ctx)
+		if err != nil {
+			if !isTransientSetupError(c.ctx, err) {
+				s.err = newSetupError(err)
+			}
+			return woodpecker.NewErrClient(newSetupError(err))
+		}
+		s.client = client
+	}
+
+	return s.client.WithContext(c.ctx)
+}
+
+func (s *lazyClientState) newClient(ctx context.Context) (woodpecker.Client, error) {
+	client, err := newClient(s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, set := range s.setters {
+		set(client)
+	}
+
+	if err = checkClient(ctx, client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// set applies the setter to the client, or defers it until the client is created.
+func (s *lazyClientState) set(setter func(woodpecker.Client)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client != nil {
+		setter(s.client)
+		return
+	}
+
+	s.setters = append(s.setters, setter)
+}
+
+// isTransientSetupError reports whether creating the client may succeed when it's retried,
+// e.g. because the server is still starting.
+func isTransientSetupError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var clientErr *woodpecker.ClientError
+	if errors.As(err, &clientErr) {
+		return clientErr.StatusCode == http.StatusTooManyRequests ||
+			clientErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// newSetupError returns the error returned by the client when it couldn't be created.
+// The cause is formatted with %v on purpose, API errors returned during the setup
+// (e.g. 404 caused by a wrong server URL) mustn't be matched by errors.Is(err, woodpecker.ErrNotFound),
+// resources would remove themselves from the state instead of failing.
+func newSetupError(err error) error {
+	return fmt.Errorf("couldn't configure the Woodpecker client: %v", err) //nolint:errorlint
+}
+
+// WithContext returns a copy of the client that uses the given context for all requests,
+// including the ones made to create the client.
+func (c *lazyClient) WithContext(ctx context.Context) woodpecker.Client {
+	return &lazyClient{state: c.state, ctx: ctx}
+}
+
+func (c *lazyClient) SetClient(client *http.Client) {
+	c.state.set(func(wc woodpecker.Client) {
+		wc.SetClient(client)
+	})
+}
+
+func (c *lazyClient) SetAddress(addr string) {
+	c.state.set(func(wc woodpecker.Client) {
+		wc.SetAddress(addr)
+	})
+}
+
+func (c *lazyClient) SetRetryPolicy(policy woodpecker.RetryPolicy) {
+	c.state.set(func(wc woodpecker.Client) {
+		wc.SetRetryPolicy(policy)
+	})
+}
+
+func (c *lazyClient) Self() (*woodpecker.User, error) {
+	return c.get().Self()
+}
+
+func (c *lazyClient) User(login string) (*woodpecker.User, error) {
+	return c.get().User(login)
+}
+
+func (c *lazyClient) UserList(opt woodpecker.UserListOptions) ([]*woodpecker.User, error) {
+	return c.get().UserList(opt)
+}
+
+func (c *lazyClient) UserPost(user *woodpecker.User) (*woodpecker.User, error) {
+	return c.get().UserPost(user)
+}
+
+func (c *lazyClient) UserPatch(user *woodpecker.User) (*woodpecker.User, error) {
+	return c.get().UserPatch(user)
+}
+
+func (c *lazyClient) UserDel(login string) error {
+	return c.get().UserDel(login)
+}
+
+func (c *lazyClient) Repo(repoID int64) (*woodpecker.Repo, error) {
+	return c.get().Repo(repoID)
+}
+
+func (c *lazyClient) RepoLookup(repoFullName string) (*woodpecker.Repo, error) {
+	return c.get().RepoLookup(repoFullName)
+}
+
+func (c *lazyClient) RepoForgeLookup(repoFullName string) (*woodpecker.Repo, error) {
+	return c.get().RepoForgeLookup(repoFullName)
+}
+
+func (c *lazyClient) RepoPermissions(repoID int64) (*woodpecker.Perm, error) {
+	return c.get().RepoPermissions(repoID)
+}
+
+func (c *lazyClient) RepoList(opt woodpecker.RepoListOptions) ([]*woodpecker.Repo, error) {
+	return c.get().RepoList(opt)
+}
+
+func (c *lazyClient) RepoPost(opt woodpecker.RepoPostOptions) (*woodpecker.Repo, error) {
+	return c.get().RepoPost(opt)
+}
+
+func (c *lazyClient) RepoPatch(repoID int64, repo *woodpecker.RepoPatch) (*woodpecker.Repo, error) {
+	return c.get().RepoPatch(repoID, repo)
+}
+
+func (c *lazyClient) RepoMove(repoID int64, opt woodpecker.RepoMoveOptions) error {
+	return c.get().RepoMove(repoID, opt)
+}
+
+func (c *lazyClient) RepoChown(repoID int64) (*woodpecker.Repo, error) {
+	return c.get().RepoChown(repoID)
+}
+
+func (c *lazyClient) RepoRepair(repoID int64) error {
+	return c.get().RepoRepair(repoID)
+}
+
+func (c *lazyClient) RepoDel(repoID int64, opt woodpecker.RepoDelOptions) error {
+	return c.get().RepoDel(repoID, opt)
+}
+
+func (c *lazyClient) Pipeline(repoID, pipeline int64) (*woodpecker.Pipeline, error) {
+	return c.get().Pipeline(repoID, pipeline)
+}
+
+func (c *lazyClient) PipelineLast(repoID int64, opt woodpecker.PipelineLastOptions) (*woodpecker.Pipeline, error) {
+	return c.get().PipelineLast(repoID, opt)
+}
+
+func (c *lazyClient) PipelineList(repoID int64, opt woodpecker.PipelineListOptions) ([]*woodpecker.Pipeline, error) {
+	return c.get().PipelineList(repoID, opt)
+}
+
+func (c *lazyClient) PipelineDelete(repoID, pipeline int64) error {
+	return c.get().PipelineDelete(repoID, pipeline)
+}
+
+func (c *lazyClient) PipelineQueue() ([]*woodpecker.Feed, error) {
+	return c.get().PipelineQueue()
+}
+
+func (c *lazyClient) PipelineCreate(repoID int64, opts *woodpecker.PipelineOptions) (*woodpecker.Pipeline, error) {
+	return c.get().PipelineCreate(repoID, opts)
+}
+
+func (c *lazyClient) PipelineStart(
+	repoID, num int64,
+	opt woodpecker.PipelineStartOptions,
+) (*woodpecker.Pipeline, error) {
+	return c.get().PipelineStart(repoID, num, opt)
+}
+
+func (c *lazyClient) PipelineStop(repoID, pipeline int64) error {
+	return c.get().PipelineStop(repoID, pipeline)
+}
+
+func (c *lazyClient) PipelineApprove(repoID, pipeline int64) (*woodpecker.Pipeline, error) {
+	return c.get().PipelineApprove(repoID, pipeline)
+}
+
+func (c *lazyClient) PipelineDecline(repoID, pipeline int64) (*woodpecker.Pipeline, error) {
+	return c.get().PipelineDecline(repoID, pipeline)
+}
+
+func (c *lazyClient) PipelineMetadata(repoID int64, pipelineNumber int) ([]byte, error) {
+	return c.get().PipelineMetadata(repoID, pipelineNumber)
+}
+
+func (c *lazyClient) StepLogEntries(repoID, pipeline, stepID int64) ([]*woodpecker.LogEntry, error) {
+	return c.get().StepLogEntries(repoID, pipeline, stepID)
+}
+
+func (c *lazyClient) StreamLogs(repoID, pipeline, stepID int64) iter.Seq2[*woodpecker.LogEntry, error] {
+	return c.get().StreamLogs(repoID, pipeline, stepID)
+}
+
+func (c *lazyClient) StreamEvents() iter.Seq2[*woodpecker.Event, error] {
+	return c.get().StreamEvents()
+}
+
+func (c *lazyClient) Deploy(repoID, pipeline int64, opt woodpecker.DeployOptions) (*woodpecker.Pipeline, error) {
+	return c.get().Deploy(repoID, pipeline, opt)
+}
+
+func (c *lazyClient) LogsPurge(repoID, pipeline int64) error {
+	return c.get().LogsPurge(repoID, pipeline)
+}
+
+func (c *lazyClient) StepLogsPurge(repoID, pipelineNumber, stepID int64) error {
+	return c.get().StepLogsPurge(repoID, pipelineNumber, stepID)
+}
+
+func (c *lazyClient) Registry(repoID int64, hostname string) (*woodpecker.Registry, error) {
+	return c.get().Registry(repoID, hostname)
+}
+
+func (c *lazyClient) RegistryList(repoID int64, opt woodpecker.RegistryListOptions) ([]*woodpecker.Registry, error) {
+	return c.get().RegistryList(repoID, opt)
+}
+
+func (c *lazyClient) RegistryCreate(repoID int64, registry *woodpecker.Registry) (*woodpecker.Registry, error) {
+	return c.get().RegistryCreate(repoID, registry)
+}
+
+func (c *lazyClient) RegistryUpdate(repoID int64, registry *woodpecker.Registry) (*woodpecker.Registry, error) {
+	return c.get().RegistryUpdate(repoID, registry)
+}
+
+func (c *lazyClient) RegistryDelete(repoID int64, hostname string) error {
+	return c.get().RegistryDelete(repoID, hostname)
+}
+
+func (c *lazyClient) OrgRegistry(orgID int64, registry string) (*woodpecker.Registry, error) {
+	return c.get().OrgRegistry(orgID, registry)
+}
+
+func (c *lazyClient) OrgRegistryList(orgID int64, opt woodpecker.RegistryListOptions) ([]*woodpecker.Registry, error) {
+	return c.get().OrgRegistryList(orgID, opt)
+}
+
+func (c *lazyClient) OrgRegistryCreate(orgID int64, registry *woodpecker.Registry) (*woodpecker.Registry, error) {
+	return c.get().OrgRegistryCreate(orgID, registry)
+}
+
+func (c *lazyClient) OrgRegistryUpdate(orgID int64, registry *woodpecker.Registry) (*woodpecker.Registry, error) {
+	return c.get().OrgRegistryUpdate(orgID, registry)
+}
+
+func (c *lazyClient) OrgRegistryDelete(orgID int64, registry string) error {
+	return c.get().OrgRegistryDelete(orgID, registry)
+}
+
+func (c *lazyClient) GlobalRegistry(registry string) (*woodpecker.Registry, error) {
+	return c.get().GlobalRegistry(registry)
+}
+
+func (c *lazyClient) GlobalRegistryList(opt woodpecker.RegistryListOptions) ([]*woodpecker.Registry, error) {
+	return c.get().GlobalRegistryList(opt)
+}
+
+func (c *lazyClient) GlobalRegistryCreate(registry *woodpecker.Registry) (*woodpecker.Registry, error) {
+	return c.get().GlobalRegistryCreate(registry)
+}
+
+func (c *lazyClient) GlobalRegistryUpdate(registry *woodpecker.Registry) (*woodpecker.Registry, error) {
+	return c.get().GlobalRegistryUpdate(registry)
+}
+
+func (c *lazyClient) GlobalRegistryDelete(registry string) error {
+	return c.get().GlobalRegistryDelete(registry)
+}
+
+func (c *lazyClient) Secret(repoID int64, secret string) (*woodpecker.Secret, error) {
+	return c.get().Secret(repoID, secret)
+}
+
+func (c *lazyClient) SecretList(repoID int64, opt woodpecker.SecretListOptions) ([]*woodpecker.Secret, error) {
+	return c.get().SecretList(repoID, opt)
+}
+
+func (c *lazyClient) SecretCreate(repoID int64, secret *woodpecker.Secret) (*woodpecker.Secret, error) {
+	return c.get().SecretCreate(repoID, secret)
+}
+
+func (c *lazyClient) SecretUpdate(repoID int64, secret *woodpecker.Secret) (*woodpecker.Secret, error) {
+	return c.get().SecretUpdate(repoID, secret)
+}
+
+func (c *lazyClient) SecretDelete(repoID int64, secret string) error {
+	return c.get().SecretDelete(repoID, secret)
+}
+
+func (c *lazyClient) Org(orgID int64) (*woodpecker.Org, error) {
+	return c.get().Org(orgID)
+}
+
+func (c *lazyClient) OrgList(opt woodpecker.OrgListOptions) ([]*woodpecker.Org, error) {
+	return c.get().OrgList(opt)
+}
+
+func (c *lazyClient) OrgLookup(orgName string) (*woodpecker.Org, error) {
+	return c.get().OrgLookup(orgName)
+}
+
+func (c *lazyClient) OrgPermissions(orgID int64) (*woodpecker.OrgPerm, error) {
+	return c.get().OrgPermissions(orgID)
+}
+
+func (c *lazyClient) OrgSecret(orgID int64, secret string) (*woodpecker.Secret, error) {
+	return c.get().OrgSecret(orgID, secret)
+}
+
+func (c *lazyClient) OrgSecretList(orgID int64, opt woodpecker.SecretListOptions) ([]*woodpecker.Secret, error) {
+	return c.get().OrgSecretList(orgID, opt)
+}
+
+func (c *lazyClient) OrgSecretCreate(orgID int64, secret *woodpecker.Secret) (*woodpecker.Secret, error) {
+	return c.get().OrgSecretCreate(orgID, secret)
+}
+
+func (c *lazyClient) OrgSecretUpdate(orgID int64, secret *woodpecker.Secret) (*woodpecker.Secret, error) {
+	return c.get().OrgSecretUpdate(orgID, secret)
+}
+
+func (c *lazyClient) OrgSecretDelete(orgID int64, secret string) error {
+	return c.get().OrgSecretDelete(orgID, secret)
+}
+
+func (c *lazyClient) OrgAgentList(orgID int64, opt woodpecker.AgentListOptions) ([]*woodpecker.Agent, error) {
+	return c.get().OrgAgentList(orgID, opt)
+}
+
+func (c *lazyClient) OrgAgentCreate(orgID int64, agent *woodpecker.Agent) (*woodpecker.Agent, error) {
+	return c.get().OrgAgentCreate(orgID, agent)
+}
+
+func (c *lazyClient) OrgAgentUpdate(orgID int64, agent *woodpecker.Agent) (*woodpecker.Agent, error) {
+	return c.get().OrgAgentUpdate(orgID, agent)
+}
+
+func (c *lazyClient) OrgAgentDelete(orgID, agentID int64) error {
+	return c.get().OrgAgentDelete(orgID, agentID)
+}
+
+func (c *lazyClient) GlobalSecret(secret string) (*woodpecker.Secret, error) {
+	return c.get().GlobalSecret(secret)
+}
+
+func (c *lazyClient) GlobalSecretList(opt woodpecker.SecretListOptions) ([]*woodpecker.Secret, error) {
+	return c.get().GlobalSecretList(opt)
+}
+
+func (c *lazyClient) GlobalSecretCreate(secret *woodpecker.Secret) (*woodpecker.Secret, error) {
+	return c.get().GlobalSecretCreate(secret)
+}
+
+func (c *lazyClient) GlobalSecretUpdate(secret *woodpecker.Secret) (*woodpecker.Secret, error) {
+	return c.get().GlobalSecretUpdate(secret)
+}
+
+func (c *lazyClient) GlobalSecretDelete(secret string) error {
+	return c.get().GlobalSecretDelete(secret)
+}
+
+func (c *lazyClient) QueueInfo() (*woodpecker.Info, error) {
+	return c.get().QueueInfo()
+}
+
+func (c *lazyClient) LogLevel() (*woodpecker.LogLevel, error) {
+	return c.get().LogLevel()
+}
+
+func (c *lazyClient) SetLogLevel(logLevel *woodpecker.LogLevel) (*woodpecker.LogLevel, error) {
+	return c.get().SetLogLevel(logLevel)
+}
+
+func (c *lazyClient) CronList(repoID int64, opt woodpecker.CronListOptions) ([]*woodpecker.Cron, error) {
+	return c.get().CronList(repoID, opt)
+}
+
+func (c *lazyClient) CronGet(repoID, cronID int64) (*woodpecker.Cron, error) {
+	return c.get().CronGet(repoID, cronID)
+}
+
+func (c *lazyClient) CronDelete(repoID, cronID int64) error {
+	return c.get().CronDelete(repoID, cronID)
+}
+
+func (c *lazyClient) CronCreate(repoID int64, cron *woodpecker.Cron) (*woodpecker.Cron, error) {
+	return c.get().CronCreate(repoID, cron)
+}
+
+func (c *lazyClient) CronUpdate(repoID int64, cron *woodpecker.Cron) (*woodpecker.Cron, error) {
+	return c.get().CronUpdate(repoID, cron)
+}
+
+func (c *lazyClient) AgentList(opt woodpecker.AgentListOptions) ([]*woodpecker.Agent, error) {
+	return c.get().AgentList(opt)
+}
+
+func (c *lazyClient) Agent(agentID int64) (*woodpecker.Agent, error) {
+	return c.get().Agent(agentID)
+}
+
+func (c *lazyClient) AgentCreate(agent *woodpecker.Agent) (*woodpecker.Agent, error) {
+	return c.get().AgentCreate(agent)
+}
+
+func (c *lazyClient) AgentUpdate(agent *woodpecker.Agent) (*woodpecker.Agent, error) {
+	return c.get().AgentUpdate(agent)
+}
+
+func (c *lazyClient) AgentDelete(agentID int64) error {
+	return c.get().AgentDelete(agentID)
+}
+
+func (c *lazyClient) AgentTasksList(agentID int64) ([]*woodpecker.Task, error) {
+	return c.get().AgentTasksList(agentID)
+}
+
+func (c *lazyClient) Version() (*woodpecker.Version, error) {
+	return c.get().Version()
+}
@@ -2,6 +2,9 @@ package internal
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -122,16 +125,62 @@ type orgModel struct {
 	ForgeID types.Int64  `tfsdk:"forge_id"`
 	Name    types.String `tfsdk:"name"`
 	IsUser  types.Bool   `tfsdk:"is_user"`
+	Admin   types.Bool   `tfsdk:"admin"`
+	Member  types.Bool   `tfsdk:"member"`
 }
 
-func (m *orgModel) setValues(_ context.Context, repo *woodpecker.Org) diag.Diagnostics {
+func (m *orgModel) setValues(_ context.Context, repo *woodpecker.Org, perm *woodpecker.OrgPerm) diag.Diagnostics {
 	m.ID = types.Int64Value(repo.ID)
 	m.ForgeID = types.Int64Value(repo.ForgeID)
 	m.Name = types.StringValue(repo.Name)
 	m.IsUser = types.BoolValue(repo.IsUser)
+	m.Admin = types.BoolValue(perm.Admin)
+	m.Member = types.BoolValue(perm.Member)
 	return nil
 }
 
+type repositoryPermissionsDataSourceModel struct {
+	RepositoryID types.Int64 `tfsdk:"repository_id"`
+	Pull         types.Bool  `tfsdk:"pull"`
+	Push         types.Bool  `tfsdk:"push"`
+	Admin        types.Bool  `tfsdk:"admin"`
+	Synced       types.Int64 `tfsdk:"synced"`
+}
+
+func (m *repositoryPermissionsDataSourceModel) setValues(perm *woodpecker.Perm) {
+	m.Pull = types.BoolValue(perm.Pull)
+	m.Push = types.BoolValue(perm.Push)
+	m.Admin = types.BoolValue(perm.Admin)
+	m.Synced = types.Int64Value(perm.Synced)
+}
+
+type orgsDataSourceModel struct {
+	NamePrefix types.String `tfsdk:"name_prefix"`
+	Orgs       types.List   `tfsdk:"orgs"`
+}
+
+func (m *orgsDataSourceModel) setValues(ctx context.Context, orgs []*woodpecker.Org) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Orgs, diags = objectListValue(ctx, orgListItemModelAttributes, orgs, orgListItemObjectValue)
+	return diags
+}
+
+var orgListItemModelAttributes = map[string]attr.Type{
+	"id":       types.Int64Type,
+	"forge_id": types.Int64Type,
+	"name":     types.StringType,
+	"is_user":  types.BoolType,
+}
+
+func orgListItemObjectValue(_ context.Context, org *woodpecker.Org) (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(orgListItemModelAttributes, map[string]attr.Value{
+		"id":       types.Int64Value(org.ID),
+		"forge_id": types.Int64Value(org.ForgeID),
+		"name":     types.StringValue(org.Name),
+		"is_user":  types.BoolValue(org.IsUser),
+	})
+}
+
 type orgSecretResourceModel struct {
 	ID             types.Int64  `tfsdk:"id"`
 	OrgID          types.Int64  `tfsdk:"org_id"`
@@ -218,6 +267,11 @@ type repositoryModel struct {
 	ConfigFile                   types.String `tfsdk:"config_file"`
 	CancelPreviousPipelineEvents types.Set    `tfsdk:"cancel_previous_pipeline_events"`
 	NetrcTrustedPlugins          types.Set    `tfsdk:"netrc_trusted_plugins"`
+	ConfigExtensionEndpoint      types.String `tfsdk:"config_extension_endpoint"`
+	RegistryExtensionEndpoint    types.String `tfsdk:"registry_extension_endpoint"`
+	SecretExtensionEndpoint      types.String `tfsdk:"secret_extension_endpoint"`
+	MaxPipelineAge               types.Int64  `tfsdk:"max_pipeline_age"`
+	LogExpiry                    types.Int64  `tfsdk:"log_expiry"`
 }
 
 var repositoryModelTrustedAttributes = map[string]attr.Type{
@@ -235,7 +289,10 @@ func (m *repositoryModel) setValues(ctx context.Context, repo *woodpecker.Repo)
 	m.ForgeRemoteID = types.StringValue(repo.ForgeRemoteID)
 	m.Owner = types.StringValue(repo.Owner)
 	m.Name = types.StringValue(repo.Name)
-	m.FullName = types.StringValue(repo.FullName)
+	// the configured name is kept if it differs only in case, names are case-insensitive on forges
+	if !strings.EqualFold(m.FullName.ValueString(), repo.FullName) {
+		m.FullName = types.StringValue(repo.FullName)
+	}
 	m.AvatarURL = types.StringValue(repo.Avatar)
 	m.ForgeURL = types.StringValue(repo.ForgeURL)
 	m.CloneURL = types.StringValue(repo.Clone)
@@ -263,10 +320,34 @@ func (m *repositoryModel) setValues(ctx context.Context, repo *woodpecker.Repo)
 	diagsRes.Append(diags...)
 	m.ApprovalAllowedUsers, diags = types.SetValueFrom(ctx, types.StringType, repo.ApprovalAllowedUsers)
 	diagsRes.Append(diags...)
+	m.ConfigExtensionEndpoint = types.StringValue(repo.ConfigExtensionEndpoint)
+	m.RegistryExtensionEndpoint = types.StringValue(repo.RegistryExtensionEndpoint)
+	m.SecretExtensionEndpoint = types.StringValue(repo.SecretExtensionEndpoint)
+	m.MaxPipelineAge = types.Int64Value(repo.MaxPipelineAge)
+	m.LogExpiry = types.Int64Value(repo.LogExpiry)
 
 	return diagsRes
 }
 
+type repositoryResourceModel struct {
+	repositoryModel
+	TakeOwnership types.Bool   `tfsdk:"take_ownership"`
+	DeleteMode    types.String `tfsdk:"delete_mode"`
+	RepairTrigger types.String `tfsdk:"repair_trigger"`
+}
+
+const (
+	repositoryDeleteModeDeactivate = "deactivate"
+	repositoryDeleteModeRemove     = "remove"
+)
+
+func (m *repositoryResourceModel) deleteMode() string {
+	if m.DeleteMode.IsNull() {
+		return repositoryDeleteModeDeactivate
+	}
+	return m.DeleteMode.ValueString()
+}
+
 type trustedConfigurationPatchModel struct {
 	Network  types.Bool `tfsdk:"network"`
 	Volumes  types.Bool `tfsdk:"volumes"`
@@ -277,10 +358,15 @@ func (m *repositoryModel) toWoodpeckerPatch(ctx context.Context) (*woodpecker.Re
 	var diags diag.Diagnostics
 
 	repo := &woodpecker.RepoPatch{
-		Config:            m.ConfigFile.ValueStringPointer(),
-		Timeout:           m.Timeout.ValueInt64Pointer(),
-		AllowPullRequests: m.AllowPullRequests.ValueBoolPointer(),
-		AllowDeployments:  m.AllowDeployments.ValueBoolPointer(),
+		Config:                    m.ConfigFile.ValueStringPointer(),
+		Timeout:                   m.Timeout.ValueInt64Pointer(),
+		AllowPullRequests:         m.AllowPullRequests.ValueBoolPointer(),
+		AllowDeployments:          m.AllowDeployments.ValueBoolPointer(),
+		ConfigExtensionEndpoint:   m.ConfigExtensionEndpoint.ValueStringPointer(),
+		RegistryExtensionEndpoint: m.RegistryExtensionEndpoint.ValueStringPointer(),
+		SecretExtensionEndpoint:   m.SecretExtensionEndpoint.ValueStringPointer(),
+		MaxPipelineAge:            m.MaxPipelineAge.ValueInt64Pointer(),
+		LogExpiry:                 m.LogExpiry.ValueInt64Pointer(),
 	}
 
 	if visibility := m.Visibility.ValueStringPointer(); visibility != nil {
@@ -305,6 +391,30 @@ func (m *repositoryModel) toWoodpeckerPatch(ctx context.Context) (*woodpecker.Re
 	return repo, diags
 }
 
+// versionedSettings returns the names of the configured attributes that require
+// a minimum Woodpecker version, see repositoryVersionedSettingsMinVersions.
+func (m *repositoryModel) versionedSettings() []string {
+	values := []struct {
+		name  string
+		value attr.Value
+	}{
+		{"config_extension_endpoint", m.ConfigExtensionEndpoint},
+		{"registry_extension_endpoint", m.RegistryExtensionEndpoint},
+		{"secret_extension_endpoint", m.SecretExtensionEndpoint},
+		{"max_pipeline_age", m.MaxPipelineAge},
+		{"log_expiry", m.LogExpiry},
+	}
+
+	var names []string
+	for _, v := range values {
+		if !v.value.IsNull() && !v.value.IsUnknown() {
+			names = append(names, v.name)
+		}
+	}
+
+	return names
+}
+
 type repositorySecretResourceModelV0 struct {
 	ID           types.Int64  `tfsdk:"id"`
 	RepositoryID types.Int64  `tfsdk:"repository_id"`
@@ -411,7 +521,7 @@ func (m *repositoryCronModel) toWoodpeckerModel(_ context.Context) (*woodpecker.
 	}, nil
 }
 
-type repositoryRegistryResourceModel struct {
+type repositoryRegistryResourceModelV0 struct {
 	ID           types.Int64  `tfsdk:"id"`
 	RepositoryID types.Int64  `tfsdk:"repository_id"`
 	Address      types.String `tfsdk:"address"`
@@ -419,21 +529,34 @@ type repositoryRegistryResourceModel struct {
 	Password     types.String `tfsdk:"password"`
 }
 
-func (m *repositoryRegistryResourceModel) setValues(_ context.Context, registry *woodpecker.Registry) diag.Diagnostics {
+type repositoryRegistryResourceModelV1 struct {
+	ID                types.Int64  `tfsdk:"id"`
+	RepositoryID      types.Int64  `tfsdk:"repository_id"`
+	Address           types.String `tfsdk:"address"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	PasswordWO        types.String `tfsdk:"password_wo"`
+	PasswordWOVersion types.Int64  `tfsdk:"password_wo_version"`
+}
+
+func (m *repositoryRegistryResourceModelV1) setValues(
+	_ context.Context,
+	registry *woodpecker.Registry,
+) diag.Diagnostics {
 	m.ID = types.Int64Value(registry.ID)
 	m.Address = types.StringValue(registry.Address)
 	m.Username = types.StringValue(registry.Username)
 	return nil
 }
 
-func (m *repositoryRegistryResourceModel) toWoodpeckerModel(
+func (m *repositoryRegistryResourceModelV1) toWoodpeckerModel(
 	_ context.Context,
 ) (*woodpecker.Registry, diag.Diagnostics) {
 	return &woodpecker.Registry{
 		ID:       m.ID.ValueInt64(),
 		Address:  m.Address.ValueString(),
 		Username: m.Username.ValueString(),
-		Password: m.Password.ValueString(),
+		Password: secretValue(m.Password, m.PasswordWO),
 	}, nil
 }
 
@@ -453,3 +576,853 @@ func (m *repositoryRegistryDataSourceModel) setValues(
 	m.Username = types.StringValue(registry.Username)
 	return nil
 }
+
+type orgRegistryResourceModel struct {
+	ID                types.Int64  `tfsdk:"id"`
+	OrgID             types.Int64  `tfsdk:"org_id"`
+	Address           types.String `tfsdk:"address"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	PasswordWO        types.String `tfsdk:"password_wo"`
+	PasswordWOVersion types.Int64  `tfsdk:"password_wo_version"`
+}
+
+func (m *orgRegistryResourceModel) setValues(_ context.Context, registry *woodpecker.Registry) diag.Diagnostics {
+	m.ID = types.Int64Value(registry.ID)
+	m.Address = types.StringValue(registry.Address)
+	m.Username = types.StringValue(registry.Username)
+	return nil
+}
+
+func (m *orgRegistryResourceModel) toWoodpeckerModel(_ context.Context) (*woodpecker.Registry, diag.Diagnostics) {
+	return &woodpecker.Registry{
+		ID:       m.ID.ValueInt64(),
+		Address:  m.Address.ValueString(),
+		Username: m.Username.ValueString(),
+		Password: secretValue(m.Password, m.PasswordWO),
+	}, nil
+}
+
+type orgRegistryDataSourceModel struct {
+	ID       types.Int64  `tfsdk:"id"`
+	OrgID    types.Int64  `tfsdk:"org_id"`
+	Address  types.String `tfsdk:"address"`
+	Username types.String `tfsdk:"username"`
+}
+
+func (m *orgRegistryDataSourceModel) setValues(_ context.Context, registry *woodpecker.Registry) diag.Diagnostics {
+	m.ID = types.Int64Value(registry.ID)
+	m.Address = types.StringValue(registry.Address)
+	m.Username = types.StringValue(registry.Username)
+	return nil
+}
+
+type globalRegistryResourceModel struct {
+	ID                types.Int64  `tfsdk:"id"`
+	Address           types.String `tfsdk:"address"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	PasswordWO        types.String `tfsdk:"password_wo"`
+	PasswordWOVersion types.Int64  `tfsdk:"password_wo_version"`
+}
+
+func (m *globalRegistryResourceModel) setValues(_ context.Context, registry *woodpecker.Registry) diag.Diagnostics {
+	m.ID = types.Int64Value(registry.ID)
+	m.Address = types.StringValue(registry.Address)
+	m.Username = types.StringValue(registry.Username)
+	return nil
+}
+
+func (m *globalRegistryResourceModel) toWoodpeckerModel(_ context.Context) (*woodpecker.Registry, diag.Diagnostics) {
+	return &woodpecker.Registry{
+		ID:       m.ID.ValueInt64(),
+		Address:  m.Address.ValueString(),
+		Username: m.Username.ValueString(),
+		Password: secretValue(m.Password, m.PasswordWO),
+	}, nil
+}
+
+type globalRegistryDataSourceModel struct {
+	ID       types.Int64  `tfsdk:"id"`
+	Address  types.String `tfsdk:"address"`
+	Username types.String `tfsdk:"username"`
+}
+
+func (m *globalRegistryDataSourceModel) setValues(_ context.Context, registry *woodpecker.Registry) diag.Diagnostics {
+	m.ID = types.Int64Value(registry.ID)
+	m.Address = types.StringValue(registry.Address)
+	m.Username = types.StringValue(registry.Username)
+	return nil
+}
+
+type agentResourceModel struct {
+	ID           types.Int64  `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	NoSchedule   types.Bool   `tfsdk:"no_schedule"`
+	CustomLabels types.Map    `tfsdk:"custom_labels"`
+	Token        types.String `tfsdk:"token"`
+	OwnerID      types.Int64  `tfsdk:"owner_id"`
+	Platform     types.String `tfsdk:"platform"`
+	Backend      types.String `tfsdk:"backend"`
+	Version      types.String `tfsdk:"version"`
+	Capacity     types.Int64  `tfsdk:"capacity"`
+	LastContact  types.Int64  `tfsdk:"last_contact"`
+}
+
+func (m *agentResourceModel) setValues(ctx context.Context, agent *woodpecker.Agent) diag.Diagnostics {
+	var diagsRes diag.Diagnostics
+	var diags diag.Diagnostics
+
+	m.ID = types.Int64Value(agent.ID)
+	m.Name = types.StringValue(agent.Name)
+	m.NoSchedule = types.BoolValue(agent.NoSchedule)
+	m.CustomLabels, diags = agentCustomLabelsValue(ctx, agent.CustomLabels)
+	diagsRes.Append(diags...)
+	// the token is only returned to admins, keep the known value otherwise
+	if agent.Token != "" {
+		m.Token = types.StringValue(agent.Token)
+	}
+	m.OwnerID = types.Int64Value(agent.OwnerID)
+	m.Platform = types.StringValue(agent.Platform)
+	m.Backend = types.StringValue(agent.Backend)
+	m.Version = types.StringValue(agent.Version)
+	m.Capacity = types.Int64Value(int64(agent.Capacity))
+	m.LastContact = types.Int64Value(agent.LastContact)
+
+	return diagsRes
+}
+
+func (m *agentResourceModel) toWoodpeckerModel(ctx context.Context) (*woodpecker.Agent, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	agent := &woodpecker.Agent{
+		ID:         m.ID.ValueInt64(),
+		Name:       m.Name.ValueString(),
+		NoSchedule: m.NoSchedule.ValueBool(),
+	}
+
+	diags.Append(m.CustomLabels.ElementsAs(ctx, &agent.CustomLabels, true)...)
+
+	return agent, diags
+}
+
+func agentCustomLabelsValue(ctx context.Context, labels map[string]string) (types.Map, diag.Diagnostics) {
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	return types.MapValueFrom(ctx, types.StringType, labels)
+}
+
+type orgAgentResourceModel struct {
+	ID           types.Int64  `tfsdk:"id"`
+	OrgID        types.Int64  `tfsdk:"org_id"`
+	Name         types.String `tfsdk:"name"`
+	NoSchedule   types.Bool   `tfsdk:"no_schedule"`
+	CustomLabels types.Map    `tfsdk:"custom_labels"`
+	Token        types.String `tfsdk:"token"`
+	OwnerID      types.Int64  `tfsdk:"owner_id"`
+	Platform     types.String `tfsdk:"platform"`
+	Backend      types.String `tfsdk:"backend"`
+	Version      types.String `tfsdk:"version"`
+	Capacity     types.Int64  `tfsdk:"capacity"`
+	LastContact  types.Int64  `tfsdk:"last_contact"`
+}
+
+func (m *orgAgentResourceModel) setValues(ctx context.Context, agent *woodpecker.Agent) diag.Diagnostics {
+	var diagsRes diag.Diagnostics
+	var diags diag.Diagnostics
+
+	m.ID = types.Int64Value(agent.ID)
+	m.OrgID = types.Int64Value(agent.OrgID)
+	m.Name = types.StringValue(agent.Name)
+	m.NoSchedule = types.BoolValue(agent.NoSchedule)
+	m.CustomLabels, diags = agentCustomLabelsValue(ctx, agent.CustomLabels)
+	diagsRes.Append(diags...)
+	if agent.Token != "" {
+		m.Token = types.StringValue(agent.Token)
+	}
+	m.OwnerID = types.Int64Value(agent.OwnerID)
+	m.Platform = types.StringValue(agent.Platform)
+	m.Backend = types.StringValue(agent.Backend)
+	m.Version = types.StringValue(agent.Version)
+	m.Capacity = types.Int64Value(int64(agent.Capacity))
+	m.LastContact = types.Int64Value(agent.LastContact)
+
+	return diagsRes
+}
+
+func (m *orgAgentResourceModel) toWoodpeckerModel(ctx context.Context) (*woodpecker.Agent, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	agent := &woodpecker.Agent{
+		ID:         m.ID.ValueInt64(),
+		OrgID:      m.OrgID.ValueInt64(),
+		Name:       m.Name.ValueString(),
+		NoSchedule: m.NoSchedule.ValueBool(),
+	}
+
+	diags.Append(m.CustomLabels.ElementsAs(ctx, &agent.CustomLabels, true)...)
+
+	return agent, diags
+}
+
+var agentModelAttributes = map[string]attr.Type{
+	"id":            types.Int64Type,
+	"org_id":        types.Int64Type,
+	"owner_id":      types.Int64Type,
+	"name":          types.StringType,
+	"platform":      types.StringType,
+	"backend":       types.StringType,
+	"capacity":      types.Int64Type,
+	"version":       types.StringType,
+	"last_contact":  types.Int64Type,
+	"no_schedule":   types.BoolType,
+	"custom_labels": types.MapType{ElemType: types.StringType},
+}
+
+func agentObjectValue(ctx context.Context, agent *woodpecker.Agent) (types.Object, diag.Diagnostics) {
+	var diagsRes diag.Diagnostics
+
+	customLabels, diags := agentCustomLabelsValue(ctx, agent.CustomLabels)
+	diagsRes.Append(diags...)
+
+	obj, diags := types.ObjectValue(agentModelAttributes, map[string]attr.Value{
+		"id":            types.Int64Value(agent.ID),
+		"org_id":        types.Int64Value(agent.OrgID),
+		"owner_id":      types.Int64Value(agent.OwnerID),
+		"name":          types.StringValue(agent.Name),
+		"platform":      types.StringValue(agent.Platform),
+		"backend":       types.StringValue(agent.Backend),
+		"capacity":      types.Int64Value(int64(agent.Capacity)),
+		"version":       types.StringValue(agent.Version),
+		"last_contact":  types.Int64Value(agent.LastContact),
+		"no_schedule":   types.BoolValue(agent.NoSchedule),
+		"custom_labels": customLabels,
+	})
+	diagsRes.Append(diags...)
+
+	return obj, diagsRes
+}
+
+type agentsDataSourceModel struct {
+	OrgID        types.Int64 `tfsdk:"org_id"`
+	CustomLabels types.Map   `tfsdk:"custom_labels"`
+	Agents       types.List  `tfsdk:"agents"`
+}
+
+func (m *agentsDataSourceModel) setValues(ctx context.Context, agents []*woodpecker.Agent) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Agents, diags = objectListValue(ctx, agentModelAttributes, agents, agentObjectValue)
+	return diags
+}
+
+var agentTaskModelAttributes = map[string]attr.Type{
+	"id":           types.StringType,
+	"labels":       types.MapType{ElemType: types.StringType},
+	"dependencies": types.ListType{ElemType: types.StringType},
+	"run_on":       types.ListType{ElemType: types.StringType},
+	"dep_status":   types.MapType{ElemType: types.StringType},
+}
+
+type agentDataSourceModel struct {
+	ID           types.Int64  `tfsdk:"id"`
+	OrgID        types.Int64  `tfsdk:"org_id"`
+	OwnerID      types.Int64  `tfsdk:"owner_id"`
+	Name         types.String `tfsdk:"name"`
+	Platform     types.String `tfsdk:"platform"`
+	Backend      types.String `tfsdk:"backend"`
+	Capacity     types.Int64  `tfsdk:"capacity"`
+	Version      types.String `tfsdk:"version"`
+	LastContact  types.Int64  `tfsdk:"last_contact"`
+	NoSchedule   types.Bool   `tfsdk:"no_schedule"`
+	CustomLabels types.Map    `tfsdk:"custom_labels"`
+	Tasks        types.List   `tfsdk:"tasks"`
+}
+
+func (m *agentDataSourceModel) setValues(
+	ctx context.Context,
+	agent *woodpecker.Agent,
+	tasks []*woodpecker.Task,
+) diag.Diagnostics {
+	var diagsRes diag.Diagnostics
+	var diags diag.Diagnostics
+
+	m.ID = types.Int64Value(agent.ID)
+	m.OrgID = types.Int64Value(agent.OrgID)
+	m.OwnerID = types.Int64Value(agent.OwnerID)
+	m.Name = types.StringValue(agent.Name)
+	m.Platform = types.StringValue(agent.Platform)
+	m.Backend = types.StringValue(agent.Backend)
+	m.Capacity = types.Int64Value(int64(agent.Capacity))
+	m.Version = types.StringValue(agent.Version)
+	m.LastContact = types.Int64Value(agent.LastContact)
+	m.NoSchedule = types.BoolValue(agent.NoSchedule)
+	m.CustomLabels, diags = agentCustomLabelsValue(ctx, agent.CustomLabels)
+	diagsRes.Append(diags...)
+
+	values := make([]attr.Value, 0, len(tasks))
+	for _, task := range tasks {
+		obj, diags := agentTaskObjectValue(ctx, task)
+		diagsRes.Append(diags...)
+		values = append(values, obj)
+	}
+	m.Tasks, diags = types.ListValue(types.ObjectType{AttrTypes: agentTaskModelAttributes}, values)
+	diagsRes.Append(diags...)
+
+	return diagsRes
+}
+
+func agentTaskObjectValue(ctx context.Context, task *woodpecker.Task) (types.Object, diag.Diagnostics) {
+	var diagsRes diag.Diagnostics
+
+	labels, diags := types.MapValueFrom(ctx, types.StringType, task.Labels)
+	diagsRes.Append(diags...)
+	dependencies, diags := types.ListValueFrom(ctx, types.StringType, task.Dependencies)
+	diagsRes.Append(diags...)
+	runOn, diags := types.ListValueFrom(ctx, types.StringType, task.RunOn)
+	diagsRes.Append(diags...)
+	depStatus, diags := types.MapValueFrom(ctx, types.StringType, task.DepStatus)
+	diagsRes.Append(diags...)
+
+	obj, diags := types.ObjectValue(agentTaskModelAttributes, map[string]attr.Value{
+		"id":           types.StringValue(task.ID),
+		"labels":       labels,
+		"dependencies": dependencies,
+		"run_on":       runOn,
+		"dep_status":   depStatus,
+	})
+	diagsRes.Append(diags...)
+
+	return obj, diagsRes
+}
+
+// objectListValue converts the given items to a list of objects using the toObject function.
+func objectListValue[T any](
+	ctx context.Context,
+	attrTypes map[string]attr.Type,
+	items []T,
+	toObject func(ctx context.Context, item T) (types.Object, diag.Diagnostics),
+) (types.List, diag.Diagnostics) {
+	var diagsRes diag.Diagnostics
+
+	values := make([]attr.Value, 0, len(items))
+	for _, item := range items {
+		obj, diags := toObject(ctx, item)
+		diagsRes.Append(diags...)
+		values = append(values, obj)
+	}
+
+	list, diags := types.ListValue(types.ObjectType{AttrTypes: attrTypes}, values)
+	diagsRes.Append(diags...)
+
+	return list, diagsRes
+}
+
+var repositoryListItemModelAttributes = map[string]attr.Type{
+	"id":              types.Int64Type,
+	"forge_id":        types.Int64Type,
+	"forge_remote_id": types.StringType,
+	"owner":           types.StringType,
+	"name":            types.StringType,
+	"full_name":       types.StringType,
+	"forge_url":       types.StringType,
+	"clone_url":       types.StringType,
+	"default_branch":  types.StringType,
+	"visibility":      types.StringType,
+	"is_private":      types.BoolType,
+	"is_active":       types.BoolType,
+}
+
+func repositoryListItemObjectValue(_ context.Context, repo *woodpecker.Repo) (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(repositoryListItemModelAttributes, map[string]attr.Value{
+		"id":              types.Int64Value(repo.ID),
+		"forge_id":        types.Int64Value(repo.ForgeID),
+		"forge_remote_id": types.StringValue(repo.ForgeRemoteID),
+		"owner":           types.StringValue(repo.Owner),
+		"name":            types.StringValue(repo.Name),
+		"full_name":       types.StringValue(repo.FullName),
+		"forge_url":       types.StringValue(repo.ForgeURL),
+		"clone_url":       types.StringValue(repo.Clone),
+		"default_branch":  types.StringValue(repo.Branch),
+		"visibility":      types.StringValue(repo.Visibility.String()),
+		"is_private":      types.BoolValue(repo.IsSCMPrivate),
+		"is_active":       types.BoolValue(repo.IsActive),
+	})
+}
+
+type repositoriesDataSourceModel struct {
+	NamePrefix   types.String `tfsdk:"name_prefix"`
+	ActiveOnly   types.Bool   `tfsdk:"active_only"`
+	Repositories types.List   `tfsdk:"repositories"`
+}
+
+func (m *repositoriesDataSourceModel) setValues(ctx context.Context, repos []*woodpecker.Repo) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Repositories, diags = objectListValue(ctx, repositoryListItemModelAttributes, repos, repositoryListItemObjectValue)
+	return diags
+}
+
+var secretListItemModelAttributes = map[string]attr.Type{
+	"id":     types.Int64Type,
+	"name":   types.StringType,
+	"images": types.SetType{ElemType: types.StringType},
+	"events": types.SetType{ElemType: types.StringType},
+}
+
+func secretListItemObjectValue(ctx context.Context, secret *woodpecker.Secret) (types.Object, diag.Diagnostics) {
+	var diagsRes diag.Diagnostics
+
+	images, diags := types.SetValueFrom(ctx, types.StringType, secret.Images)
+	diagsRes.Append(diags...)
+	events, diags := types.SetValueFrom(ctx, types.StringType, secret.Events)
+	diagsRes.Append(diags...)
+
+	obj, diags := types.ObjectValue(secretListItemModelAttributes, map[string]attr.Value{
+		"id":     types.Int64Value(secret.ID),
+		"name":   types.StringValue(secret.Name),
+		"images": images,
+		"events": events,
+	})
+	diagsRes.Append(diags...)
+
+	return obj, diagsRes
+}
+
+type secretsDataSourceModel struct {
+	NamePrefix types.String `tfsdk:"name_prefix"`
+	Secrets    types.List   `tfsdk:"secrets"`
+}
+
+func (m *secretsDataSourceModel) setValues(ctx context.Context, secrets []*woodpecker.Secret) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Secrets, diags = objectListValue(ctx, secretListItemModelAttributes, secrets, secretListItemObjectValue)
+	return diags
+}
+
+type orgSecretsDataSourceModel struct {
+	OrgID      types.Int64  `tfsdk:"org_id"`
+	NamePrefix types.String `tfsdk:"name_prefix"`
+	Secrets    types.List   `tfsdk:"secrets"`
+}
+
+func (m *orgSecretsDataSourceModel) setValues(ctx context.Context, secrets []*woodpecker.Secret) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Secrets, diags = objectListValue(ctx, secretListItemModelAttributes, secrets, secretListItemObjectValue)
+	return diags
+}
+
+type repositorySecretsDataSourceModel struct {
+	RepositoryID types.Int64  `tfsdk:"repository_id"`
+	NamePrefix   types.String `tfsdk:"name_prefix"`
+	Secrets      types.List   `tfsdk:"secrets"`
+}
+
+func (m *repositorySecretsDataSourceModel) setValues(
+	ctx context.Context,
+	secrets []*woodpecker.Secret,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Secrets, diags = objectListValue(ctx, secretListItemModelAttributes, secrets, secretListItemObjectValue)
+	return diags
+}
+
+var repositoryCronListItemModelAttributes = map[string]attr.Type{
+	"id":         types.Int64Type,
+	"name":       types.StringType,
+	"creator_id": types.Int64Type,
+	"schedule":   types.StringType,
+	"created_at": types.Int64Type,
+	"branch":     types.StringType,
+}
+
+func repositoryCronListItemObjectValue(_ context.Context, cron *woodpecker.Cron) (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(repositoryCronListItemModelAttributes, map[string]attr.Value{
+		"id":         types.Int64Value(cron.ID),
+		"name":       types.StringValue(cron.Name),
+		"creator_id": types.Int64Value(cron.CreatorID),
+		"schedule":   types.StringValue(cron.Schedule),
+		"created_at": types.Int64Value(cron.Created),
+		"branch":     types.StringValue(cron.Branch),
+	})
+}
+
+type repositoryCronsDataSourceModel struct {
+	RepositoryID types.Int64  `tfsdk:"repository_id"`
+	NamePrefix   types.String `tfsdk:"name_prefix"`
+	Crons        types.List   `tfsdk:"crons"`
+}
+
+func (m *repositoryCronsDataSourceModel) setValues(ctx context.Context, crons []*woodpecker.Cron) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Crons, diags = objectListValue(
+		ctx,
+		repositoryCronListItemModelAttributes,
+		crons,
+		repositoryCronListItemObjectValue,
+	)
+	return diags
+}
+
+var registryListItemModelAttributes = map[string]attr.Type{
+	"id":       types.Int64Type,
+	"address":  types.StringType,
+	"username": types.StringType,
+}
+
+func registryListItemObjectValue(_ context.Context, registry *woodpecker.Registry) (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(registryListItemModelAttributes, map[string]attr.Value{
+		"id":       types.Int64Value(registry.ID),
+		"address":  types.StringValue(registry.Address),
+		"username": types.StringValue(registry.Username),
+	})
+}
+
+type repositoryRegistriesDataSourceModel struct {
+	RepositoryID  types.Int64  `tfsdk:"repository_id"`
+	AddressPrefix types.String `tfsdk:"address_prefix"`
+	Registries    types.List   `tfsdk:"registries"`
+}
+
+func (m *repositoryRegistriesDataSourceModel) setValues(
+	ctx context.Context,
+	registries []*woodpecker.Registry,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Registries, diags = objectListValue(ctx, registryListItemModelAttributes, registries, registryListItemObjectValue)
+	return diags
+}
+
+type pipelineResourceModel struct {
+	ID                types.Int64  `tfsdk:"id"`
+	RepositoryID      types.Int64  `tfsdk:"repository_id"`
+	Branch            types.String `tfsdk:"branch"`
+	Variables         types.Map    `tfsdk:"variables"`
+	Triggers          types.Map    `tfsdk:"triggers"`
+	WaitForCompletion types.Bool   `tfsdk:"wait_for_completion"`
+	Timeout           types.Int64  `tfsdk:"timeout"`
+	Number            types.Int64  `tfsdk:"number"`
+	Status            types.String `tfsdk:"status"`
+	Commit            types.String `tfsdk:"commit"`
+	Workflows         types.List   `tfsdk:"workflows"`
+}
+
+func (m *pipelineResourceModel) setValues(ctx context.Context, pipeline *woodpecker.Pipeline) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.ID = types.Int64Value(pipeline.ID)
+	m.Number = types.Int64Value(pipeline.Number)
+	m.Branch = types.StringValue(pipeline.Branch)
+	m.Status = types.StringValue(pipeline.Status)
+	m.Commit = types.StringValue(pipeline.Commit)
+	m.Workflows, diags = objectListValue(
+		ctx,
+		pipelineWorkflowModelAttributes,
+		pipeline.Workflows,
+		pipelineWorkflowObjectValue,
+	)
+
+	return diags
+}
+
+func (m *pipelineResourceModel) toWoodpeckerOptions(
+	ctx context.Context,
+) (*woodpecker.PipelineOptions, diag.Diagnostics) {
+	opts := &woodpecker.PipelineOptions{
+		Branch:    m.Branch.ValueString(),
+		Variables: make(map[string]string),
+	}
+	diags := m.Variables.ElementsAs(ctx, &opts.Variables, false)
+	return opts, diags
+}
+
+// timeout returns how long to wait for the pipeline to finish.
+func (m *pipelineResourceModel) timeout() time.Duration {
+	if m.Timeout.IsNull() || m.Timeout.IsUnknown() {
+		return pipelineDefaultTimeout * time.Minute
+	}
+	return time.Duration(m.Timeout.ValueInt64()) * time.Minute
+}
+
+type deploymentResourceModel struct {
+	ID                types.Int64  `tfsdk:"id"`
+	RepositoryID      types.Int64  `tfsdk:"repository_id"`
+	PipelineNumber    types.Int64  `tfsdk:"pipeline_number"`
+	Commit            types.String `tfsdk:"commit"`
+	Environment       types.String `tfsdk:"environment"`
+	Params            types.Map    `tfsdk:"params"`
+	Triggers          types.Map    `tfsdk:"triggers"`
+	WaitForCompletion types.Bool   `tfsdk:"wait_for_completion"`
+	Timeout           types.Int64  `tfsdk:"timeout"`
+	Number            types.Int64  `tfsdk:"number"`
+	Status            types.String `tfsdk:"status"`
+}
+
+func (m *deploymentResourceModel) setValues(pipeline *woodpecker.Pipeline) {
+	m.ID = types.Int64Value(pipeline.ID)
+	m.Number = types.Int64Value(pipeline.Number)
+	// the deployed pipeline is only known from the parent after import
+	if m.PipelineNumber.IsNull() || m.PipelineNumber.IsUnknown() {
+		m.PipelineNumber = types.Int64Value(pipeline.Parent)
+	}
+	m.Environment = types.StringValue(pipeline.Deploy)
+	m.Status = types.StringValue(pipeline.Status)
+}
+
+func (m *deploymentResourceModel) toWoodpeckerOptions(
+	ctx context.Context,
+) (woodpecker.DeployOptions, diag.Diagnostics) {
+	opts := woodpecker.DeployOptions{
+		DeployTo: m.Environment.ValueString(),
+		Params:   make(map[string]string),
+	}
+	diags := m.Params.ElementsAs(ctx, &opts.Params, false)
+	return opts, diags
+}
+
+// timeout returns how long to wait for the deployment pipeline to finish.
+func (m *deploymentResourceModel) timeout() time.Duration {
+	if m.Timeout.IsNull() || m.Timeout.IsUnknown() {
+		return pipelineDefaultTimeout * time.Minute
+	}
+	return time.Duration(m.Timeout.ValueInt64()) * time.Minute
+}
+
+var pipelineStepModelAttributes = map[string]attr.Type{
+	"id":        types.Int64Type,
+	"pid":       types.Int64Type,
+	"name":      types.StringType,
+	"type":      types.StringType,
+	"state":     types.StringType,
+	"error":     types.StringType,
+	"exit_code": types.Int64Type,
+	"started":   types.Int64Type,
+	"stopped":   types.Int64Type,
+}
+
+func pipelineStepObjectValue(_ context.Context, step *woodpecker.Step) (types.Object, diag.Diagnostics) {
+	return types.ObjectValue(pipelineStepModelAttributes, map[string]attr.Value{
+		"id":        types.Int64Value(step.ID),
+		"pid":       types.Int64Value(int64(step.PID)),
+		"name":      types.StringValue(step.Name),
+		"type":      types.StringValue(string(step.Type)),
+		"state":     types.StringValue(step.State),
+		"error":     types.StringValue(step.Error),
+		"exit_code": types.Int64Value(int64(step.ExitCode)),
+		"started":   types.Int64Value(step.Started),
+		"stopped":   types.Int64Value(step.Stopped),
+	})
+}
+
+var pipelineWorkflowModelAttributes = map[string]attr.Type{
+	"id":      types.Int64Type,
+	"pid":     types.Int64Type,
+	"name":    types.StringType,
+	"state":   types.StringType,
+	"error":   types.StringType,
+	"started": types.Int64Type,
+	"stopped": types.Int64Type,
+	"steps":   types.ListType{ElemType: types.ObjectType{AttrTypes: pipelineStepModelAttributes}},
+}
+
+func pipelineWorkflowObjectValue(ctx context.Context, workflow *woodpecker.Workflow) (types.Object, diag.Diagnostics) {
+	var diagsRes diag.Diagnostics
+
+	steps, diags := objectListValue(ctx, pipelineStepModelAttributes, workflow.Children, pipelineStepObjectValue)
+	diagsRes.Append(diags...)
+
+	obj, diags := types.ObjectValue(pipelineWorkflowModelAttributes, map[string]attr.Value{
+		"id":      types.Int64Value(workflow.ID),
+		"pid":     types.Int64Value(int64(workflow.PID)),
+		"name":    types.StringValue(workflow.Name),
+		"state":   types.StringValue(workflow.State),
+		"error":   types.StringValue(workflow.Error),
+		"started": types.Int64Value(workflow.Started),
+		"stopped": types.Int64Value(workflow.Stopped),
+		"steps":   steps,
+	})
+	diagsRes.Append(diags...)
+
+	return obj, diagsRes
+}
+
+var pipelineModelAttributes = map[string]attr.Type{
+	"id":        types.Int64Type,
+	"number":    types.Int64Type,
+	"parent":    types.Int64Type,
+	"event":     types.StringType,
+	"status":    types.StringType,
+	"branch":    types.StringType,
+	"commit":    types.StringType,
+	"ref":       types.StringType,
+	"message":   types.StringType,
+	"author":    types.StringType,
+	"sender":    types.StringType,
+	"deploy_to": types.StringType,
+	"forge_url": types.StringType,
+	"created":   types.Int64Type,
+	"started":   types.Int64Type,
+	"finished":  types.Int64Type,
+	"workflows": types.ListType{ElemType: types.ObjectType{AttrTypes: pipelineWorkflowModelAttributes}},
+}
+
+func pipelineObjectValue(ctx context.Context, pipeline *woodpecker.Pipeline) (types.Object, diag.Diagnostics) {
+	var diagsRes diag.Diagnostics
+
+	workflows, diags := objectListValue(
+		ctx,
+		pipelineWorkflowModelAttributes,
+		pipeline.Workflows,
+		pipelineWorkflowObjectValue,
+	)
+	diagsRes.Append(diags...)
+
+	obj, diags := types.ObjectValue(pipelineModelAttributes, map[string]attr.Value{
+		"id":        types.Int64Value(pipeline.ID),
+		"number":    types.Int64Value(pipeline.Number),
+		"parent":    types.Int64Value(pipeline.Parent),
+		"event":     types.StringValue(pipeline.Event),
+		"status":    types.StringValue(pipeline.Status),
+		"branch":    types.StringValue(pipeline.Branch),
+		"commit":    types.StringValue(pipeline.Commit),
+		"ref":       types.StringValue(pipeline.Ref),
+		"message":   types.StringValue(pipeline.Message),
+		"author":    types.StringValue(pipeline.Author),
+		"sender":    types.StringValue(pipeline.Sender),
+		"deploy_to": types.StringValue(pipeline.Deploy),
+		"forge_url": types.StringValue(pipeline.ForgeURL),
+		"created":   types.Int64Value(pipeline.Created),
+		"started":   types.Int64Value(pipeline.Started),
+		"finished":  types.Int64Value(pipeline.Finished),
+		"workflows": workflows,
+	})
+	diagsRes.Append(diags...)
+
+	return obj, diagsRes
+}
+
+type pipelineDataSourceModel struct {
+	RepositoryID types.Int64  `tfsdk:"repository_id"`
+	Number       types.Int64  `tfsdk:"number"`
+	Branch       types.String `tfsdk:"branch"`
+	ID           types.Int64  `tfsdk:"id"`
+	Parent       types.Int64  `tfsdk:"parent"`
+	Event        types.String `tfsdk:"event"`
+	Status       types.String `tfsdk:"status"`
+	Commit       types.String `tfsdk:"commit"`
+	Ref          types.String `tfsdk:"ref"`
+	Message      types.String `tfsdk:"message"`
+	Author       types.String `tfsdk:"author"`
+	Sender       types.String `tfsdk:"sender"`
+	DeployTo     types.String `tfsdk:"deploy_to"`
+	ForgeURL     types.String `tfsdk:"forge_url"`
+	Created      types.Int64  `tfsdk:"created"`
+	Started      types.Int64  `tfsdk:"started"`
+	Finished     types.Int64  `tfsdk:"finished"`
+	Workflows    types.List   `tfsdk:"workflows"`
+}
+
+func (m *pipelineDataSourceModel) setValues(ctx context.Context, pipeline *woodpecker.Pipeline) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.ID = types.Int64Value(pipeline.ID)
+	m.Number = types.Int64Value(pipeline.Number)
+	m.Parent = types.Int64Value(pipeline.Parent)
+	m.Event = types.StringValue(pipeline.Event)
+	m.Status = types.StringValue(pipeline.Status)
+	m.Branch = types.StringValue(pipeline.Branch)
+	m.Commit = types.StringValue(pipeline.Commit)
+	m.Ref = types.StringValue(pipeline.Ref)
+	m.Message = types.StringValue(pipeline.Message)
+	m.Author = types.StringValue(pipeline.Author)
+	m.Sender = types.StringValue(pipeline.Sender)
+	m.DeployTo = types.StringValue(pipeline.Deploy)
+	m.ForgeURL = types.StringValue(pipeline.ForgeURL)
+	m.Created = types.Int64Value(pipeline.Created)
+	m.Started = types.Int64Value(pipeline.Started)
+	m.Finished = types.Int64Value(pipeline.Finished)
+	m.Workflows, diags = objectListValue(
+		ctx,
+		pipelineWorkflowModelAttributes,
+		pipeline.Workflows,
+		pipelineWorkflowObjectValue,
+	)
+
+	return diags
+}
+
+type pipelineStepLogDataSourceModel struct {
+	RepositoryID   types.Int64  `tfsdk:"repository_id"`
+	PipelineNumber types.Int64  `tfsdk:"pipeline_number"`
+	StepName       types.String `tfsdk:"step_name"`
+	WorkflowName   types.String `tfsdk:"workflow_name"`
+	Types          types.Set    `tfsdk:"types"`
+	Tail           types.Int64  `tfsdk:"tail"`
+	StepID         types.Int64  `tfsdk:"step_id"`
+	State          types.String `tfsdk:"state"`
+	ExitCode       types.Int64  `tfsdk:"exit_code"`
+	Log            types.String `tfsdk:"log"`
+}
+
+func (m *pipelineStepLogDataSourceModel) setValues(step *woodpecker.Step, log string) {
+	m.StepID = types.Int64Value(step.ID)
+	m.State = types.StringValue(step.State)
+	m.ExitCode = types.Int64Value(int64(step.ExitCode))
+	m.Log = types.StringValue(log)
+}
+
+func (m *pipelineStepLogDataSourceModel) toWoodpeckerLogDecodeOptions(
+	ctx context.Context,
+) (woodpecker.LogDecodeOptions, diag.Diagnostics) {
+	opts := woodpecker.LogDecodeOptions{
+		Tail: int(m.Tail.ValueInt64()),
+	}
+
+	names := []string{woodpecker.LogEntryStdout.String(), woodpecker.LogEntryStderr.String()}
+	if !m.Types.IsNull() {
+		if diags := m.Types.ElementsAs(ctx, &names, false); diags.HasError() {
+			return opts, diags
+		}
+	}
+
+	// the names are validated by the schema
+	for _, t := range logEntryTypes {
+		if slices.Contains(names, t.String()) {
+			opts.Types = append(opts.Types, t)
+		}
+	}
+
+	return opts, nil
+}
+
+type pipelinesDataSourceModel struct {
+	RepositoryID types.Int64  `tfsdk:"repository_id"`
+	Branch       types.String `tfsdk:"branch"`
+	Events       types.Set    `tfsdk:"events"`
+	Status       types.String `tfsdk:"status"`
+	Limit        types.Int64  `tfsdk:"limit"`
+	Pipelines    types.List   `tfsdk:"pipelines"`
+}
+
+func (m *pipelinesDataSourceModel) setValues(ctx context.Context, pipelines []*woodpecker.Pipeline) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.Pipelines, diags = objectListValue(ctx, pipelineModelAttributes, pipelines, pipelineObjectValue)
+	return diags
+}
+
+func (m *pipelinesDataSourceModel) limit() int {
+	if m.Limit.IsNull() {
+		return pipelinesDefaultLimit
+	}
+	return int(m.Limit.ValueInt64())
+}
+
+func (m *pipelinesDataSourceModel) toWoodpeckerListOptions(
+	ctx context.Context,
+) (woodpecker.PipelineListOptions, diag.Diagnostics) {
+	opts := woodpecker.PipelineListOptions{
+		Branch: m.Branch.ValueString(),
+		Status: m.Status.ValueString(),
+	}
+	diags := m.Events.ElementsAs(ctx, &opts.Events, false)
+	return opts, diags
+}
@@ -2,16 +2,27 @@ package internal
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
 	"github.com/Masterminds/semver/v3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"golang.org/x/oauth2"
 )
@@ -42,6 +53,8 @@ func (p *woodpeckerProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 		Description: "A Terraform provider used to interact with Woodpecker CI resources.",
 		MarkdownDescription: "A Terraform provider used to interact with" +
 			" [Woodpecker CI](https://woodpecker-ci.org/) resources." +
+			" The provider connects to the server on the first API call, so `server` and `token`" +
+			" can reference values that are known only after apply (e.g. attributes of other resources)." +
 			"\n\n\n- v0.4.x and later versions of the provider work with Woodpecker 3.x+" +
 			"\n- v0.2.x and v0.3.x versions of the provider work with Woodpecker 3.0.0>1.x>=2.0.0" +
 			"\n- v0.1.x version of the provider works with Woodpecker 2.0.0>1.x>=1.0.0",
@@ -58,6 +71,86 @@ func (p *woodpeckerProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 					can also be sourced from the WOODPECKER_TOKEN environment
 					variable.`,
 			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				Description: `The maximum number of times a failed request is retried. Requests are retried
+					on 429, 502 and 503 responses (POST and PATCH requests only on 429) and on network errors.
+					Set to 0 to disable retries. Defaults to 3. Can also be sourced from the
+					WOODPECKER_MAX_RETRIES environment variable.`,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional: true,
+				Description: `The time to wait before the first retry (e.g. 500ms, 1s), doubled with every
+					following retry. Defaults to 1s. Can also be sourced from the WOODPECKER_RETRY_WAIT_MIN
+					environment variable.`,
+			},
+			"retry_wait_max": schema.StringAttribute{
+				Optional: true,
+				Description: `The maximum time to wait between retries (e.g. 30s, 1m). A Retry-After header
+					sent by the server is honored up to this limit, a longer Retry-After is shortened to it.
+					Defaults to 30s. Can also be sourced from the WOODPECKER_RETRY_WAIT_MAX environment variable.`,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional: true,
+				Description: `PEM-encoded CA certificates used to verify the server certificate, in addition to
+					the system roots. Conflicts with ca_cert_file. Can also be sourced from the
+					WOODPECKER_CA_CERT_PEM environment variable.`,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_file")),
+				},
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional: true,
+				Description: `Path to a file with PEM-encoded CA certificates used to verify the server certificate,
+					in addition to the system roots. Conflicts with ca_cert_pem. Can also be sourced from the
+					WOODPECKER_CA_CERT_FILE environment variable.`,
+			},
+			"client_cert": schema.StringAttribute{
+				Optional: true,
+				Description: `PEM-encoded client certificate used for mutual TLS, requires client_key.
+					Can also be sourced from the WOODPECKER_CLIENT_CERT environment variable.`,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: `PEM-encoded private key of client_cert, requires client_cert.
+					Can also be sourced from the WOODPECKER_CLIENT_KEY environment variable.`,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional: true,
+				Description: `Skips the verification of the server certificate. Use only for testing.
+					Defaults to false. Can also be sourced from the WOODPECKER_INSECURE_SKIP_VERIFY
+					environment variable.`,
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional: true,
+				Description: `URL of the proxy used to connect to the server (e.g. http://proxy:3128).
+					Defaults to the proxy from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+					Can also be sourced from the WOODPECKER_PROXY_URL environment variable.`,
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional: true,
+				Description: `The maximum time a single request may take (e.g. 30s, 1m), including reading
+					the response. Log and event streams aren't limited. Defaults to no timeout.
+					Can also be sourced from the WOODPECKER_REQUEST_TIMEOUT environment variable.`,
+			},
+			"extra_headers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: `Additional HTTP headers sent with every request (e.g. headers required by
+					a reverse proxy). The Authorization header is always set from token.
+					Can also be sourced from the WOODPECKER_EXTRA_HEADERS environment variable
+					as a comma-separated list of name=value pairs.`,
+			},
 		},
 	}
 }
@@ -67,11 +160,26 @@ func (p *woodpeckerProvider) DataSources(_ context.Context) []func() datasource.
 		newUserDataSource,
 		newSecretDataSource,
 		newOrgDataSource,
+		newOrgsDataSource,
 		newOrgSecretDataSource,
 		newRepositoryDataSource,
+		newRepositoryPermissionsDataSource,
 		newRepositorySecretDataSource,
 		newRepositoryCronDataSource,
 		newRepositoryRegistryDataSource,
+		newOrgRegistryDataSource,
+		newGlobalRegistryDataSource,
+		newAgentDataSource,
+		newAgentsDataSource,
+		newRepositoriesDataSource,
+		newSecretsDataSource,
+		newOrgSecretsDataSource,
+		newRepositorySecretsDataSource,
+		newRepositoryCronsDataSource,
+		newRepositoryRegistriesDataSource,
+		newPipelineDataSource,
+		newPipelinesDataSource,
+		newPipelineStepLogDataSource,
 	}
 }
 
@@ -84,6 +192,12 @@ func (p *woodpeckerProvider) Resources(_ context.Context) []func() resource.Reso
 		newRepositorySecretResource,
 		newRepositoryCronResource,
 		newRepositoryRegistryResource,
+		newOrgRegistryResource,
+		newGlobalRegistryResource,
+		newAgentResource,
+		newOrgAgentResource,
+		newPipelineResource,
+		newDeploymentResource,
 	}
 }
 
@@ -97,18 +211,84 @@ func (p *woodpeckerProvider) Configure(
 		return
 	}
 
-	client := newClient(ctx, cfg, resp)
-	if resp.Diagnostics.HasError() {
+	// server or token may depend on resources that haven't been created yet (e.g. the Woodpecker server itself),
+	// Terraform calls Configure again with known values during apply
+	if cfg.isUnknown() && req.ClientCapabilities.DeferralAllowed {
+		resp.Deferred = &provider.Deferred{
+			Reason: provider.DeferredReasonProviderConfigUnknown,
+		}
 		return
 	}
 
+	client := newLazyClient(cfg)
+
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+const (
+	defaultMaxRetries   = 3
+	defaultRetryWaitMin = time.Second
+	defaultRetryWaitMax = 30 * time.Second
+)
+
 type providerConfig struct {
-	Server types.String `tfsdk:"server"`
-	Token  types.String `tfsdk:"token"`
+	Server       types.String `tfsdk:"server"`
+	Token        types.String `tfsdk:"token"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax types.String `tfsdk:"retry_wait_max"`
+
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	RequestTimeout     types.String `tfsdk:"request_timeout"`
+	ExtraHeaders       types.Map    `tfsdk:"extra_headers"`
+
+	retryPolicy woodpecker.RetryPolicy
+	// httpClient is the client the oauth2 transport is built on top of,
+	// it's nil when the transport attributes are unknown
+	httpClient *http.Client
+}
+
+// isUnknown reports whether the values required to connect to the server are unknown.
+func (c providerConfig) isUnknown() bool {
+	return c.Server.IsUnknown() || c.Token.IsUnknown() || c.isTransportUnknown()
+}
+
+// isTransportUnknown reports whether the values required to build the transport are unknown.
+func (c providerConfig) isTransportUnknown() bool {
+	return c.CACertPEM.IsUnknown() ||
+		c.CACertFile.IsUnknown() ||
+		c.ClientCert.IsUnknown() ||
+		c.ClientKey.IsUnknown() ||
+		c.InsecureSkipVerify.IsUnknown() ||
+		c.ProxyURL.IsUnknown() ||
+		c.RequestTimeout.IsUnknown() ||
+		c.ExtraHeaders.IsUnknown()
+}
+
+// validate returns an error if the values required to connect to the server are missing.
+func (c providerConfig) validate() error {
+	if c.isUnknown() {
+		return errors.New("the provider configuration depends on values that aren't known yet " +
+			"(e.g. server or token), they're known after the resources they depend on are created")
+	}
+
+	if c.Server.ValueString() == "" {
+		return errors.New("the server URL was not found in the WOODPECKER_SERVER environment variable " +
+			"or provider configuration block server attribute")
+	}
+
+	if c.Token.ValueString() == "" {
+		return errors.New("the API token was not found in the WOODPECKER_TOKEN environment variable " +
+			"or provider configuration block token attribute")
+	}
+
+	return nil
 }
 
 func newProviderConfig(
@@ -122,96 +302,345 @@ func newProviderConfig(
 		return config
 	}
 
-	if config.Server.ValueString() == "" {
+	// missing values are reported on the first API call, so that the provider
+	// can be configured (e.g. for terraform validate) without access to the server
+	if config.Server.IsNull() || (!config.Server.IsUnknown() && config.Server.ValueString() == "") {
 		config.Server = types.StringValue(os.Getenv("WOODPECKER_SERVER"))
 	}
 
-	if config.Server.ValueString() == "" {
+	if config.Token.IsNull() || (!config.Token.IsUnknown() && config.Token.ValueString() == "") {
+		config.Token = types.StringValue(os.Getenv("WOODPECKER_TOKEN"))
+	}
+
+	config.retryPolicy = newRetryPolicy(config, resp)
+
+	if !config.isTransportUnknown() {
+		config.httpClient = newHTTPClient(ctx, config, resp)
+	}
+
+	return config
+}
+
+func newRetryPolicy(config providerConfig, resp *provider.ConfigureResponse) woodpecker.RetryPolicy {
+	policy := woodpecker.RetryPolicy{
+		MaxRetries: defaultMaxRetries,
+		WaitMin:    defaultRetryWaitMin,
+		WaitMax:    defaultRetryWaitMax,
+	}
+
+	if !config.MaxRetries.IsNull() {
+		policy.MaxRetries = int(config.MaxRetries.ValueInt64())
+	} else if env := os.Getenv("WOODPECKER_MAX_RETRIES"); env != "" {
+		maxRetries, err := strconv.Atoi(env)
+		if err != nil || maxRetries < 0 {
+			resp.Diagnostics.AddError(
+				"Invalid Max Retries Configuration",
+				fmt.Sprintf("WOODPECKER_MAX_RETRIES must be a non-negative integer, got: %q", env),
+			)
+		}
+		policy.MaxRetries = maxRetries
+	}
+
+	policy.WaitMin = parseDurationConfig(
+		config.RetryWaitMin,
+		"retry_wait_min",
+		"WOODPECKER_RETRY_WAIT_MIN",
+		policy.WaitMin,
+		resp,
+	)
+	policy.WaitMax = parseDurationConfig(
+		config.RetryWaitMax,
+		"retry_wait_max",
+		"WOODPECKER_RETRY_WAIT_MAX",
+		policy.WaitMax,
+		resp,
+	)
+
+	if policy.WaitMin > policy.WaitMax {
 		resp.Diagnostics.AddError(
-			"Missing Server URL Configuration",
-			"While configuring the provider, the server URL was not found in "+
-				"the WOODPECKER_SERVER environment variable or provider "+
-				"configuration block server attribute.",
+			"Invalid Retry Wait Configuration",
+			fmt.Sprintf(
+				"retry_wait_min (%s) must not be greater than retry_wait_max (%s)",
+				policy.WaitMin,
+				policy.WaitMax,
+			),
 		)
 	}
 
-	if config.Token.ValueString() == "" {
-		config.Token = types.StringValue(os.Getenv("WOODPECKER_TOKEN"))
+	return policy
+}
+
+// parseDurationConfig returns the duration from the given attribute,
+// falls back to the environment variable and then to the default value.
+func parseDurationConfig(
+	value types.String,
+	attrName string,
+	envName string,
+	defaultValue time.Duration,
+	resp *provider.ConfigureResponse,
+) time.Duration {
+	raw := value.ValueString()
+	source := attrName
+	if raw == "" {
+		raw = os.Getenv(envName)
+		source = envName
+	}
+
+	if raw == "" {
+		return defaultValue
 	}
 
-	if config.Token.ValueString() == "" {
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
 		resp.Diagnostics.AddError(
-			"Missing API Token Configuration",
-			"While configuring the provider, the API token was not found in "+
-				"the WOODPECKER_TOKEN environment variable or provider "+
-				"configuration block token attribute.",
+			"Invalid Duration Configuration",
+			fmt.Sprintf("%s must be a non-negative duration (e.g. 500ms, 1s, 1m), got: %q", source, raw),
 		)
+		return defaultValue
 	}
 
-	return config
+	return d
 }
 
-func newClient(
-	ctx context.Context,
-	config providerConfig,
-	resp *provider.ConfigureResponse,
-) woodpecker.Client {
+// newHTTPClient returns the client with the transport built from the TLS, proxy, timeout and header settings,
+// each setting falls back to its environment variable.
+func newHTTPClient(ctx context.Context, config providerConfig, resp *provider.ConfigureResponse) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = newTLSConfig(config, resp)
+
+	if proxyURL := stringConfig(config.ProxyURL, "WOODPECKER_PROXY_URL"); proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Proxy URL Configuration",
+				fmt.Sprintf("proxy_url must be an absolute URL (e.g. http://proxy:3128), got: %q", proxyURL),
+			)
+		} else {
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
+
+	var rt http.RoundTripper = transport
+	if headers := newExtraHeaders(ctx, config, resp); len(headers) > 0 {
+		rt = &headerTransport{base: rt, headers: headers}
+	}
+
+	return &http.Client{
+		Transport: rt,
+		Timeout: parseDurationConfig(
+			config.RequestTimeout,
+			"request_timeout",
+			"WOODPECKER_REQUEST_TIMEOUT",
+			0,
+			resp,
+		),
+	}
+}
+
+func newTLSConfig(config providerConfig, resp *provider.ConfigureResponse) *tls.Config {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if !config.InsecureSkipVerify.IsNull() {
+		tlsConfig.InsecureSkipVerify = config.InsecureSkipVerify.ValueBool()
+	} else if env := os.Getenv("WOODPECKER_INSECURE_SKIP_VERIFY"); env != "" {
+		insecure, err := strconv.ParseBool(env)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Insecure Skip Verify Configuration",
+				fmt.Sprintf("WOODPECKER_INSECURE_SKIP_VERIFY must be a boolean, got: %q", env),
+			)
+		}
+		tlsConfig.InsecureSkipVerify = insecure
+	}
+
+	// the environment variables are used only if neither of the attributes is set
+	caCertPEM := config.CACertPEM.ValueString()
+	caCertFile := config.CACertFile.ValueString()
+	if caCertPEM == "" && caCertFile == "" {
+		caCertPEM = os.Getenv("WOODPECKER_CA_CERT_PEM")
+		caCertFile = os.Getenv("WOODPECKER_CA_CERT_FILE")
+	}
+	switch {
+	case caCertPEM != "" && caCertFile != "":
+		resp.Diagnostics.AddError(
+			"Invalid CA Certificate Configuration",
+			"Only one of ca_cert_pem (WOODPECKER_CA_CERT_PEM) and ca_cert_file (WOODPECKER_CA_CERT_FILE) can be set.",
+		)
+	case caCertFile != "":
+		b, err := os.ReadFile(caCertFile)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid CA Certificate Configuration",
+				fmt.Sprintf("Couldn't read ca_cert_file: %s", err),
+			)
+			break
+		}
+		caCertPEM = string(b)
+	}
+
+	if caCertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caCertPEM)) {
+			resp.Diagnostics.AddError(
+				"Invalid CA Certificate Configuration",
+				"No valid PEM-encoded certificates were found in the CA certificates.",
+			)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	clientCert := stringConfig(config.ClientCert, "WOODPECKER_CLIENT_CERT")
+	clientKey := stringConfig(config.ClientKey, "WOODPECKER_CLIENT_KEY")
+	if clientCert != "" || clientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Client Certificate Configuration",
+				fmt.Sprintf("Couldn't load the client certificate and key: %s", err),
+			)
+		} else {
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	return tlsConfig
+}
+
+func newExtraHeaders(ctx context.Context, config providerConfig, resp *provider.ConfigureResponse) http.Header {
+	headers := make(http.Header)
+
+	if !config.ExtraHeaders.IsNull() {
+		var values map[string]string
+		resp.Diagnostics.Append(config.ExtraHeaders.ElementsAs(ctx, &values, false)...)
+		for name, value := range values {
+			headers.Set(name, value)
+		}
+		return headers
+	}
+
+	env := os.Getenv("WOODPECKER_EXTRA_HEADERS")
+	if env == "" {
+		return headers
+	}
+
+	for pair := range strings.SplitSeq(env, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			resp.Diagnostics.AddError(
+				"Invalid Extra Headers Configuration",
+				fmt.Sprintf(
+					"WOODPECKER_EXTRA_HEADERS must be a comma-separated list of name=value pairs, got: %q",
+					env,
+				),
+			)
+			return headers
+		}
+		headers.Set(name, strings.TrimSpace(value))
+	}
+
+	return headers
+}
+
+// stringConfig returns the value of the given attribute, falls back to the environment variable.
+func stringConfig(value types.String, envName string) string {
+	if v := value.ValueString(); v != "" {
+		return v
+	}
+	return os.Getenv(envName)
+}
+
+// headerTransport sets the given headers on every request,
+// headers already set on the request (e.g. Authorization set by the oauth2 transport) take precedence.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		if _, ok := req.Header[name]; !ok {
+			req.Header[name] = values
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// woodpeckerMinVersion is the minimum Woodpecker version supported by the provider.
+const woodpeckerMinVersion = ">= 3.0.0"
+
+// newClient creates the Woodpecker client, it's checked by checkClient before it's used.
+func newClient(config providerConfig) (woodpecker.Client, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	// the token is set on top of the configured transport
 	client := woodpecker.NewClient(
 		config.Server.ValueString(),
-		(&oauth2.Config{}).Client(ctx, &oauth2.Token{
-			AccessToken: config.Token.ValueString(),
-		}),
+		&http.Client{
+			Transport: &oauth2.Transport{
+				Source: oauth2.StaticTokenSource(&oauth2.Token{
+					AccessToken: config.Token.ValueString(),
+				}),
+				Base: config.httpClient.Transport,
+			},
+			Timeout: config.httpClient.Timeout,
+		},
 	)
+	client.SetRetryPolicy(config.retryPolicy)
 
-	_, err := client.Self()
+	return client, nil
+}
+
+// checkClient checks that the token is valid and the server version is supported.
+func checkClient(ctx context.Context, client woodpecker.Client) error {
+	_, err := client.WithContext(ctx).Self()
 	if err != nil {
-		resp.Diagnostics.AddError("Couldn't get current user", err.Error())
-		return nil
+		return fmt.Errorf("couldn't get current user: %w", err)
 	}
 
-	c, err := semver.NewConstraint(">= 3.0.0")
+	c, err := semver.NewConstraint(woodpeckerMinVersion)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Couldn't parse woodpecker version constraint",
-			fmt.Sprintf(
-				"%s. Please report this issue to the provider developers.",
-				err,
-			),
+		return fmt.Errorf(
+			"couldn't parse woodpecker version constraint: %w. Please report this issue to the provider developers",
+			err,
 		)
-		return nil
 	}
 
-	ver, err := client.Version()
+	ver, err := client.WithContext(ctx).Version()
 	if err != nil {
-		resp.Diagnostics.AddError("Couldn't get woodpecker version", err.Error())
-		return nil
+		return fmt.Errorf("couldn't get woodpecker version: %w", err)
 	}
 
-	// split is required because in some cases the version looks like this: 2.0.0-f05c1631d2
-	parsedVer, err := semver.NewVersion(strings.Split(ver.Version, "-")[0])
+	parsedVer, err := parseWoodpeckerVersion(ver.Version)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Couldn't parse woodpecker version",
-			fmt.Sprintf(
-				"%s. Please report this issue to the provider developers.",
-				err,
-			),
+		return fmt.Errorf(
+			"couldn't parse woodpecker version: %w. Please report this issue to the provider developers",
+			err,
 		)
-		return nil
 	}
 
 	if !c.Check(parsedVer) {
-		resp.Diagnostics.AddError(
-			"Woodpecker version doesn't satisfy the constraint",
-			fmt.Sprintf(
-				"Current woodpecker version: %s, expected: %s."+
-					" Consider using an older version of the provider or update your Woodpecker CI instance.",
-				ver.Version,
-				c.String(),
-			),
+		return fmt.Errorf(
+			"woodpecker version doesn't satisfy the constraint, current version: %s, expected: %s."+
+				" Consider using an older version of the provider or update your Woodpecker CI instance",
+			ver.Version,
+			c.String(),
 		)
-		return nil
 	}
 
-	return client
+	return nil
+}
+
+// parseWoodpeckerVersion parses the version reported by the /version endpoint.
+func parseWoodpeckerVersion(raw string) (*semver.Version, error) {
+	// split is required because in some cases the version looks like this: 2.0.0-f05c1631d2
+	return semver.NewVersion(strings.Split(raw, "-")[0])
 }
@@ -1,12 +1,39 @@
 package internal_test
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
+	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
@@ -24,3 +51,746 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("WOODPECKER_TOKEN must be set for tests")
 	}
 }
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK: retries", func(t *testing.T) {
+		t.Parallel()
+
+		// failures are injected into a separate fake server, so that other tests aren't affected
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		srv.FailNext(2, http.StatusServiceUnavailable)
+		srv.FailNext(1, http.StatusTooManyRequests)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	token = "%s"
+	max_retries = 5
+	retry_wait_min = "10ms"
+	retry_wait_max = "100ms"
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, srv.URL, woodpeckertest.Token),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("data.woodpecker_user.current", "login", woodpeckertest.SelfLogin),
+						func(_ *terraform.State) error {
+							// 3 failed attempts and at least one successful request
+							if got := srv.Requests(); got < 4 {
+								return fmt.Errorf("got %d requests, want at least 4", got)
+							}
+							return nil
+						},
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: retries disabled", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		srv.FailNext(1, http.StatusServiceUnavailable)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	token = "%s"
+	max_retries = 0
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, srv.URL, woodpeckertest.Token),
+					ExpectError: regexp.MustCompile("client error 503"),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: invalid retry_wait_min", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+provider "woodpecker" {
+	retry_wait_min = "1 second"
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`,
+					ExpectError: regexp.MustCompile("Invalid Duration Configuration"),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: retry_wait_min greater than retry_wait_max", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+provider "woodpecker" {
+	retry_wait_min = "1m"
+	retry_wait_max = "1s"
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`,
+					ExpectError: regexp.MustCompile("Invalid Retry Wait Configuration"),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: server unknown during plan", func(t *testing.T) {
+		t.Parallel()
+
+		name := uuid.NewString()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+				tfversion.SkipBelow(tfversion.Version1_4_0),
+			},
+			CheckDestroy: checkSecretResourceDestroy(name),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "terraform_data" "server" {
+	input = "%s"
+}
+
+provider "woodpecker" {
+	server = terraform_data.server.output
+}
+
+resource "woodpecker_secret" "test_secret" {
+	name = "%s"
+	value = "test123"
+	events = ["%s"]
+}
+`, os.Getenv("WOODPECKER_SERVER"), name, woodpecker.EventPush),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_secret.test_secret", "id"),
+						resource.TestCheckResourceAttr("woodpecker_secret.test_secret", "name", name),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: server unreachable", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+provider "woodpecker" {
+	server = "http://127.0.0.1:1"
+	max_retries = 0
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`,
+					ExpectError: regexp.MustCompile("couldn't get current user"),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: current user not found", func(t *testing.T) {
+		t.Parallel()
+
+		// e.g. a wrong base path in the server URL
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		name := uuid.NewString()
+		config := fmt.Sprintf(`
+resource "woodpecker_secret" "test_secret" {
+	name = "%s"
+	value = "test123"
+	events = ["%s"]
+}
+`, name, woodpecker.EventPush)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkSecretResourceDestroy(name),
+			Steps: []resource.TestStep{
+				{
+					Config: config,
+				},
+				{ // the read must fail instead of removing the secret from the state
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+}
+`, srv.URL) + config,
+					ExpectError: regexp.MustCompile("couldn't get current user"),
+				},
+				{ // the secret is still in the state
+					Config:   config,
+					PlanOnly: true,
+				},
+			},
+		})
+	})
+
+	t.Run("OK: ca_cert_pem, request_timeout and extra_headers", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &requestRecorder{}
+		srv := httptest.NewTLSServer(newWoodpeckerProxy(t, recorder))
+		defer srv.Close()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	ca_cert_pem = %q
+	request_timeout = "30s"
+	extra_headers = {
+		"X-Terraform-Test" = "true"
+		"Authorization" = "Bearer invalid"
+	}
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, srv.URL, certPEM(srv.Certificate())),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("data.woodpecker_user.current", "id"),
+						recorder.checkHeader("X-Terraform-Test", "true"),
+						// extra_headers can't override the token
+						recorder.checkHeader("Authorization", "Bearer "+os.Getenv("WOODPECKER_TOKEN")),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: ca_cert_file", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &requestRecorder{}
+		srv := httptest.NewTLSServer(newWoodpeckerProxy(t, recorder))
+		defer srv.Close()
+
+		caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caCertFile, []byte(certPEM(srv.Certificate())), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	ca_cert_file = %q
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, srv.URL, caCertFile),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("data.woodpecker_user.current", "id"),
+						recorder.checkRequests(),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: client_cert and client_key", func(t *testing.T) {
+		t.Parallel()
+
+		clientCert, clientCertPEM, clientKeyPEM := newClientCert(t)
+
+		recorder := &requestRecorder{}
+		srv := httptest.NewUnstartedServer(newWoodpeckerProxy(t, recorder))
+		srv.TLS = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			ClientCAs:  x509.NewCertPool(),
+		}
+		srv.TLS.ClientCAs.AddCert(clientCert)
+		srv.StartTLS()
+		defer srv.Close()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	ca_cert_pem = %q
+	client_cert = %q
+	client_key = %q
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, srv.URL, certPEM(srv.Certificate()), clientCertPEM, clientKeyPEM),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("data.woodpecker_user.current", "id"),
+						recorder.checkClientCert(clientCert),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: proxy_url", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &requestRecorder{}
+		// requests sent through a proxy have the absolute URL of the target server
+		proxy := httptest.NewServer(recorder.wrap(&httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.Out.Host = pr.In.Host
+			},
+		}))
+		defer proxy.Close()
+
+		server, err := url.Parse(os.Getenv("WOODPECKER_SERVER"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	proxy_url = "%s"
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, proxy.URL),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("data.woodpecker_user.current", "id"),
+						recorder.checkHost(server.Host),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: untrusted server certificate", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewTLSServer(newWoodpeckerProxy(t, &requestRecorder{}))
+		defer srv.Close()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	max_retries = 0
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, srv.URL),
+					ExpectError: regexp.MustCompile("certificate signed by unknown authority"),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: missing client certificate", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewUnstartedServer(newWoodpeckerProxy(t, &requestRecorder{}))
+		srv.TLS = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+		}
+		srv.StartTLS()
+		defer srv.Close()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	ca_cert_pem = %q
+	max_retries = 0
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`, srv.URL, certPEM(srv.Certificate())),
+					ExpectError: regexp.MustCompile(`tls: certificate required`),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: invalid ca_cert_pem", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+provider "woodpecker" {
+	ca_cert_pem = "not a certificate"
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`,
+					ExpectError: regexp.MustCompile("Invalid CA Certificate Configuration"),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: client_cert without client_key", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+provider "woodpecker" {
+	client_cert = "cert"
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`,
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: invalid proxy_url", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+provider "woodpecker" {
+	proxy_url = "proxy:3128"
+}
+
+data "woodpecker_user" "current" {
+	login = ""
+}
+`,
+					ExpectError: regexp.MustCompile("Invalid Proxy URL Configuration"),
+				},
+			},
+		})
+	})
+}
+
+func TestLazyClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK: methods called without WithContext", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+
+		user, err := internal.NewLazyClient(srv.URL, woodpeckertest.Token).Self()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Login != woodpeckertest.SelfLogin {
+			t.Errorf("got login %s, want %s", user.Login, woodpeckertest.SelfLogin)
+		}
+	})
+
+	t.Run("OK: address set before the client is created", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+
+		client := internal.NewLazyClient("http://127.0.0.1:1", woodpeckertest.Token)
+		client.SetAddress(srv.URL)
+
+		if _, err := client.WithContext(context.Background()).Self(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("OK: transient setup errors aren't cached", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		srv.FailNext(1, http.StatusServiceUnavailable)
+
+		client := internal.NewLazyClient(srv.URL, woodpeckertest.Token)
+
+		if _, err := client.Self(); err == nil || !strings.Contains(err.Error(), "couldn't configure") {
+			t.Fatalf("got error %v, want setup error", err)
+		}
+		if _, err := client.Self(); err != nil {
+			t.Fatalf("got error %v, want the client to be created on the next call", err)
+		}
+	})
+
+	t.Run("OK: network setup errors aren't cached", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+
+		// nothing listens on the address until the test server is started
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := ln.Addr().String()
+		_ = ln.Close()
+
+		client := internal.NewLazyClient("http://"+addr, woodpeckertest.Token)
+		if _, err = client.Self(); err == nil {
+			t.Fatal("got nil error, want setup error")
+		}
+
+		// the fake server is served on the address the client was configured with
+		late := &http.Server{
+			Handler:           srv,
+			ReadHeaderTimeout: time.Second,
+		}
+		ln, err = net.Listen("tcp", addr)
+		if err != nil {
+			t.Skipf("couldn't listen on %s again: %s", addr, err)
+		}
+		go func() { _ = late.Serve(ln) }()
+		t.Cleanup(func() { _ = late.Close() })
+
+		if _, err = client.Self(); err != nil {
+			t.Fatalf("got error %v, want the client to be created once the server is up", err)
+		}
+	})
+
+	t.Run("ERR: setup errors are cached", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+
+		client := internal.NewLazyClient(srv.URL, "invalid")
+
+		if _, err := client.Self(); err == nil {
+			t.Fatal("got nil error, want setup error")
+		}
+
+		requests := srv.Requests()
+		_, err := client.RepoList(woodpecker.RepoListOptions{})
+		if err == nil || !strings.Contains(err.Error(), "couldn't get current user") {
+			t.Errorf("got error %v, want the cached setup error", err)
+		}
+		if n := srv.Requests() - requests; n != 0 {
+			t.Errorf("got %d requests, want 0", n)
+		}
+	})
+}
+
+// newWoodpeckerProxy returns a handler that forwards requests to the Woodpecker server used in tests
+// and records them in recorder.
+func newWoodpeckerProxy(tb testing.TB, recorder *requestRecorder) http.Handler {
+	tb.Helper()
+
+	target, err := url.Parse(os.Getenv("WOODPECKER_SERVER"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return recorder.wrap(httputil.NewSingleHostReverseProxy(target))
+}
+
+// requestRecorder records the requests served by the handlers it wraps.
+type requestRecorder struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (r *requestRecorder) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.requests = append(r.requests, req.Clone(context.Background()))
+		r.mu.Unlock()
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// check returns a resource.TestCheckFunc that fails if no requests have been recorded
+// or if fn returns an error for any of them.
+func (r *requestRecorder) check(fn func(req *http.Request) error) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if len(r.requests) == 0 {
+			return errors.New("no requests have been recorded")
+		}
+
+		for _, req := range r.requests {
+			if err := fn(req); err != nil {
+				return fmt.Errorf("%s %s: %w", req.Method, req.URL, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+func (r *requestRecorder) checkRequests() resource.TestCheckFunc {
+	return r.check(func(*http.Request) error {
+		return nil
+	})
+}
+
+func (r *requestRecorder) checkHeader(name, value string) resource.TestCheckFunc {
+	return r.check(func(req *http.Request) error {
+		if got := req.Header.Get(name); got != value {
+			return fmt.Errorf("got %s header %q, want %q", name, got, value)
+		}
+		return nil
+	})
+}
+
+func (r *requestRecorder) checkHost(host string) resource.TestCheckFunc {
+	return r.check(func(req *http.Request) error {
+		if req.URL.Host != host {
+			return fmt.Errorf("got request to %q, want %q", req.URL.Host, host)
+		}
+		return nil
+	})
+}
+
+func (r *requestRecorder) checkClientCert(cert *x509.Certificate) resource.TestCheckFunc {
+	return r.check(func(req *http.Request) error {
+		if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+			return errors.New("no client certificate has been presented")
+		}
+		if !req.TLS.PeerCertificates[0].Equal(cert) {
+			return fmt.Errorf("got client certificate %q, want %q", req.TLS.PeerCertificates[0].Subject, cert.Subject)
+		}
+		return nil
+	})
+}
+
+// newClientCert returns a self-signed client certificate, it can be used as its own CA.
+func newClientCert(tb testing.TB) (*x509.Certificate, string, string) {
+	tb.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform-provider-woodpecker"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return cert, certPEM(cert), string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func certPEM(cert *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
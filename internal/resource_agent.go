@@ -0,0 +1,247 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type agentResource struct {
+	client woodpecker.Client
+}
+
+var _ resource.Resource = (*agentResource)(nil)
+var _ resource.ResourceWithConfigure = (*agentResource)(nil)
+var _ resource.ResourceWithImportState = (*agentResource)(nil)
+
+func newAgentResource() resource.Resource {
+	return &agentResource{}
+}
+
+func (r *agentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_agent"
+}
+
+func (r *agentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This resource allows you to register/remove agents." +
+			" When applied, a new agent will be registered and its token will be available in the token attribute." +
+			" When destroyed, that agent will be removed." +
+			" For more information see" +
+			" [the Woodpecker docs](https://woodpecker-ci.org/docs/administration/configuration/agent).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the agent's id",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "the name of the agent",
+			},
+			"no_schedule": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "whether new tasks should not be scheduled on this agent",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"custom_labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Description: "custom labels assigned to the agent, used to filter the tasks it picks up",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "the token the agent uses to authenticate with the server",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"owner_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the ID of the user who registered the agent",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"platform": schema.StringAttribute{
+				Computed:    true,
+				Description: "the platform reported by the agent (e.g. linux/amd64)",
+			},
+			"backend": schema.StringAttribute{
+				Computed:    true,
+				Description: "the backend reported by the agent (e.g. docker, kubernetes, local)",
+			},
+			"version": schema.StringAttribute{
+				Computed:    true,
+				Description: "the version reported by the agent",
+			},
+			"capacity": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the number of workflows the agent can run in parallel",
+			},
+			"last_contact": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the time the agent last contacted the server (unix timestamp)",
+			},
+		},
+	}
+}
+
+func (r *agentResource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *agentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data agentResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wData, diags := data.toWoodpeckerModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.client.WithContext(ctx).AgentCreate(wData)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't create agent", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, agent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *agentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data agentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.client.WithContext(ctx).Agent(data.ID.ValueInt64())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get agent", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, agent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *agentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data agentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wData, diags := data.toWoodpeckerModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.client.WithContext(ctx).AgentUpdate(wData)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't update agent", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, agent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *agentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data agentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.WithContext(ctx).AgentDelete(data.ID.ValueInt64()); err != nil {
+		resp.Diagnostics.AddError("Couldn't delete agent", err.Error())
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *agentResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	id, err := strconv.ParseInt(req.ID, 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid agent id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,87 @@
+package internal_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAgentResource(t *testing.T) {
+	t.Parallel()
+
+	name := uuid.NewString()
+	newName := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             checkAgentResourceDestroy(name, newName),
+		Steps: []resource.TestStep{
+			{ // create agent
+				Config: fmt.Sprintf(`
+resource "woodpecker_agent" "test_agent" {
+	name = "%s"
+}
+`, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("woodpecker_agent.test_agent", "id"),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "name", name),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "no_schedule", "false"),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "custom_labels.%", "0"),
+					resource.TestCheckResourceAttrSet("woodpecker_agent.test_agent", "token"),
+					resource.TestCheckResourceAttrSet("woodpecker_agent.test_agent", "owner_id"),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "capacity", "0"),
+				),
+			},
+			{ // update agent
+				Config: fmt.Sprintf(`
+resource "woodpecker_agent" "test_agent" {
+	name = "%s"
+	no_schedule = true
+	custom_labels = {
+		"location" = "europe"
+	}
+}
+`, newName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("woodpecker_agent.test_agent", "id"),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "name", newName),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "no_schedule", "true"),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "custom_labels.%", "1"),
+					resource.TestCheckResourceAttr("woodpecker_agent.test_agent", "custom_labels.location", "europe"),
+					resource.TestCheckResourceAttrSet("woodpecker_agent.test_agent", "token"),
+				),
+			},
+			{ // import
+				ResourceName:      "woodpecker_agent.test_agent",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func checkAgentResourceDestroy(names ...string) func(state *terraform.State) error {
+	return func(_ *terraform.State) error {
+		agents, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Agent, error) {
+			return woodpeckerClient.AgentList(woodpecker.AgentListOptions{ListOptions: opt})
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't list agents: %w", err)
+		}
+
+		if slices.ContainsFunc(agents, func(agent *woodpecker.Agent) bool {
+			return slices.Contains(names, agent.Name)
+		}) {
+			return errors.New("at least one of the created agents isn't deleted")
+		}
+
+		return nil
+	}
+}
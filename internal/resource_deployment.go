@@ -0,0 +1,385 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type deploymentResource struct {
+	client woodpecker.Client
+}
+
+var _ resource.Resource = (*deploymentResource)(nil)
+var _ resource.ResourceWithConfigure = (*deploymentResource)(nil)
+var _ resource.ResourceWithConfigValidators = (*deploymentResource)(nil)
+var _ resource.ResourceWithImportState = (*deploymentResource)(nil)
+
+func newDeploymentResource() resource.Resource {
+	return &deploymentResource{}
+}
+
+func (r *deploymentResource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_deployment"
+}
+
+func (r *deploymentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This resource allows you to deploy a pipeline to an environment." +
+			" When applied, a new deployment pipeline will be started from the given pipeline" +
+			" (or from the latest pipeline of the given commit) and, if `wait_for_completion` is set," +
+			" the apply will wait until it finishes and fail if it ends in failure, error or killed." +
+			" A new deployment is started whenever `pipeline_number`, `commit`, `environment`, `params`" +
+			" or `triggers` change. When destroyed, the deployment pipeline will be stopped if it's still running" +
+			" (finished pipelines are kept in the history). The repository must have deployments allowed." +
+			" For more information see" +
+			" [the Woodpecker docs](https://woodpecker-ci.org/docs/usage/workflow-syntax#event-deployment).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the id of the deployment pipeline",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"pipeline_number": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "the number of the pipeline to deploy, exactly one of `pipeline_number` or `commit` must be set",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplaceIf(
+						func(
+							_ context.Context,
+							req planmodifier.Int64Request,
+							resp *int64planmodifier.RequiresReplaceIfFuncResponse,
+						) {
+							// the pipeline number resolved from commit is unknown at plan time
+							resp.RequiresReplace = !req.ConfigValue.IsNull()
+						},
+						"If the value of this attribute is configured and changes, Terraform will destroy and recreate the resource.",
+						"If the value of this attribute is configured and changes, Terraform will destroy and recreate the resource.",
+					),
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"commit": schema.StringAttribute{
+				Optional: true,
+				Description: "the commit SHA to deploy, its latest non-deployment pipeline is used," +
+					" exactly one of `pipeline_number` or `commit` must be set",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"environment": schema.StringAttribute{
+				Required:    true,
+				Description: "the environment to deploy to, e.g. staging or production",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"params": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "custom parameters injected into the environment of the deployment pipeline",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "arbitrary map of values that, when changed, will start a new deployment",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait_for_completion": schema.BoolAttribute{
+				Optional:    true,
+				Description: "whether to wait until the deployment pipeline finishes, defaults to false",
+			},
+			"timeout": schema.Int64Attribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"how long to wait for the deployment pipeline to finish (in minutes), defaults to %d",
+					pipelineDefaultTimeout,
+				),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"number": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the number of the deployment pipeline",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "the status of the deployment pipeline",
+			},
+		},
+	}
+}
+
+func (r *deploymentResource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *deploymentResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("pipeline_number"),
+			path.MatchRoot("commit"),
+		),
+	}
+}
+
+func (r *deploymentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data deploymentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repoID := data.RepositoryID.ValueInt64()
+
+	if !data.Commit.IsNull() {
+		number, err := findPipelineByCommit(ctx, r.client, repoID, data.Commit.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't find pipeline to deploy", err.Error())
+			return
+		}
+		data.PipelineNumber = types.Int64Value(number)
+	}
+
+	opts, diags := data.toWoodpeckerOptions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pipeline, err := r.client.WithContext(ctx).Deploy(repoID, data.PipelineNumber.ValueInt64(), opts)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't create deployment", err.Error())
+		return
+	}
+
+	if data.WaitForCompletion.ValueBool() {
+		var finished *woodpecker.Pipeline
+		finished, err = waitForPipeline(ctx, r.client, repoID, pipeline.Number, data.timeout())
+		if finished != nil {
+			pipeline = finished
+		}
+	}
+
+	data.setValues(pipeline)
+
+	// the state is saved even if the deployment failed, so that Terraform marks the resource as tainted
+	// and starts a new deployment on the next apply
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't wait for deployment", err.Error())
+		return
+	}
+
+	if data.WaitForCompletion.ValueBool() && isPipelineFailed(pipeline.Status) {
+		resp.Diagnostics.AddError(
+			"Deployment failed",
+			fmt.Sprintf("Deployment pipeline #%d finished with status %q", pipeline.Number, pipeline.Status)+
+				failedStepsLog(ctx, r.client, repoID, pipeline),
+		)
+	}
+}
+
+func (r *deploymentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data deploymentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pipeline, err := r.client.WithContext(ctx).Pipeline(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get deployment", err.Error())
+		return
+	}
+
+	if pipeline.Event != woodpecker.EventDeploy {
+		resp.Diagnostics.AddError(
+			"Couldn't get deployment",
+			fmt.Sprintf("Pipeline #%d isn't a deployment (event: %q)", pipeline.Number, pipeline.Event),
+		)
+		return
+	}
+
+	data.setValues(pipeline)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *deploymentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data deploymentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only wait_for_completion and timeout can be updated in place,
+	// they don't affect the deployment that has already been started
+	pipeline, err := r.client.WithContext(ctx).Pipeline(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get deployment", err.Error())
+		return
+	}
+
+	data.setValues(pipeline)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *deploymentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data deploymentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pipeline, err := r.client.WithContext(ctx).Pipeline(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get deployment", err.Error())
+		return
+	}
+
+	if !isPipelineFinished(pipeline.Status) {
+		err = r.client.WithContext(ctx).PipelineStop(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't stop deployment", err.Error())
+			return
+		}
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *deploymentResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	idParts := strings.Split(req.ID, importStateIDSeparator)
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: repository_id/number. Got: %q", req.ID),
+		)
+		return
+	}
+
+	repoID, err := strconv.ParseInt(idParts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid repository id", err.Error())
+		return
+	}
+
+	number, err := strconv.ParseInt(idParts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid pipeline number", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("repository_id"), repoID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("number"), number)...)
+}
+
+// findPipelineByCommit returns the number of the latest non-deployment pipeline for the given commit.
+func findPipelineByCommit(
+	ctx context.Context,
+	client woodpecker.Client,
+	repoID int64,
+	commit string,
+) (int64, error) {
+	for pipeline, err := range woodpecker.Paginate(func(opt woodpecker.ListOptions) ([]*woodpecker.Pipeline, error) {
+		return client.WithContext(ctx).PipelineList(repoID, woodpecker.PipelineListOptions{ListOptions: opt})
+	}) {
+		if err != nil {
+			return 0, err
+		}
+		if pipeline.Commit == commit && pipeline.Event != woodpecker.EventDeploy {
+			return pipeline.Number, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no pipeline found for commit %q", commit)
+}
@@ -0,0 +1,313 @@
+package internal_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestDeploymentResource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		allowDeployments(t, repo)
+		pipeline := createPipeline(t, repo, giteaRepo.DefaultBranch)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkDeploymentResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{ // deploy pipeline by number
+					Config: fmt.Sprintf(`
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = %d
+	pipeline_number = %d
+	environment = "staging"
+	params = {
+		FOO = "bar"
+	}
+	triggers = {
+		version = "1"
+	}
+}
+`, repo.ID, pipeline.Number),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_deployment.test_deployment", "id"),
+						resource.TestCheckResourceAttr(
+							"woodpecker_deployment.test_deployment",
+							"repository_id",
+							strconv.FormatInt(repo.ID, 10),
+						),
+						resource.TestCheckResourceAttr(
+							"woodpecker_deployment.test_deployment",
+							"pipeline_number",
+							strconv.FormatInt(pipeline.Number, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "environment", "staging"),
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "number", "2"),
+						resource.TestCheckResourceAttrSet("woodpecker_deployment.test_deployment", "status"),
+					),
+				},
+				{ // update wait settings in place
+					Config: fmt.Sprintf(`
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = %d
+	pipeline_number = %d
+	environment = "staging"
+	params = {
+		FOO = "bar"
+	}
+	triggers = {
+		version = "1"
+	}
+	timeout = 5
+}
+`, repo.ID, pipeline.Number),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_deployment.test_deployment",
+								plancheck.ResourceActionUpdate,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "number", "2"),
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "timeout", "5"),
+					),
+				},
+				{ // deploy to another environment
+					Config: fmt.Sprintf(`
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = %d
+	pipeline_number = %d
+	environment = "production"
+	params = {
+		FOO = "bar"
+	}
+	triggers = {
+		version = "1"
+	}
+	timeout = 5
+}
+`, repo.ID, pipeline.Number),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_deployment.test_deployment",
+								plancheck.ResourceActionDestroyBeforeCreate,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "environment", "production"),
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "number", "3"),
+					),
+				},
+				{ // deploy commit
+					Config: fmt.Sprintf(`
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = %d
+	commit = "%s"
+	environment = "production"
+	params = {
+		FOO = "bar"
+	}
+	triggers = {
+		version = "1"
+	}
+	timeout = 5
+}
+`, repo.ID, pipeline.Commit),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_deployment.test_deployment",
+								plancheck.ResourceActionDestroyBeforeCreate,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "commit", pipeline.Commit),
+						resource.TestCheckResourceAttr(
+							"woodpecker_deployment.test_deployment",
+							"pipeline_number",
+							strconv.FormatInt(pipeline.Number, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_deployment.test_deployment", "number", "4"),
+					),
+				},
+				{ // import
+					ResourceName:      "woodpecker_deployment.test_deployment",
+					ImportState:       true,
+					ImportStateVerify: true,
+					ImportStateVerifyIgnore: []string{
+						"commit",
+						"params",
+						"triggers",
+						"timeout",
+						"status",
+					},
+					ImportStateIdFunc: func(state *terraform.State) (string, error) {
+						rs, ok := state.RootModule().Resources["woodpecker_deployment.test_deployment"]
+						if !ok {
+							return "", errors.New("woodpecker_deployment.test_deployment not found in state")
+						}
+						return rs.Primary.Attributes["repository_id"] + "/" + rs.Primary.Attributes["number"], nil
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: deployment failed", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		allowDeployments(t, repo)
+		pipeline := createPipeline(t, repo, giteaRepo.DefaultBranch)
+		finishPipelines(t, repo, woodpecker.StatusFailure, logLines(25)...)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkDeploymentResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = %d
+	pipeline_number = %d
+	environment = "staging"
+	wait_for_completion = true
+}
+`, repo.ID, pipeline.Number),
+					// only the last 20 lines of the failed step are included
+					ExpectError: regexp.MustCompile(
+						`(?s)Deployment failed.*Deployment pipeline #2 finished with status "failure".*` +
+							`Step "build" \(workflow "woodpecker"\) exited with code 1:[\s│]+line 6[\s│]+line 7[\s│].*line 25`,
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: timeout", func(t *testing.T) {
+		t.Parallel()
+
+		// there is no agent, the deployment pipeline stays pending
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		allowDeployments(t, repo)
+		pipeline := createPipeline(t, repo, giteaRepo.DefaultBranch)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkDeploymentResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = %d
+	pipeline_number = %d
+	environment = "staging"
+	wait_for_completion = true
+	timeout = 1
+}
+`, repo.ID, pipeline.Number),
+					ExpectError: regexp.MustCompile(
+						`(?s)Couldn't wait for deployment.*pipeline #2 didn't finish within 1m0s`,
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: deployments not allowed", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		pipeline := createPipeline(t, repo, giteaRepo.DefaultBranch)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = %d
+	pipeline_number = %d
+	environment = "staging"
+}
+`, repo.ID, pipeline.Number),
+					ExpectError: regexp.MustCompile(`Couldn't create deployment`),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: pipeline_number and commit are mutually exclusive", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+resource "woodpecker_deployment" "test_deployment" {
+	repository_id = 1
+	pipeline_number = 1
+	commit = "abc"
+	environment = "staging"
+}
+`,
+					ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+				},
+			},
+		})
+	})
+}
+
+func checkDeploymentResourceDestroy(repoID int64) func(state *terraform.State) error {
+	return func(_ *terraform.State) error {
+		pipelines, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Pipeline, error) {
+			return woodpeckerClient.PipelineList(repoID, woodpecker.PipelineListOptions{
+				ListOptions: opt,
+				Events:      []string{woodpecker.EventDeploy},
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't list pipelines: %w", err)
+		}
+
+		for _, pipeline := range pipelines {
+			switch pipeline.Status {
+			case woodpecker.StatusPending, woodpecker.StatusRunning:
+				return fmt.Errorf("deployment pipeline #%d is still %s", pipeline.Number, pipeline.Status)
+			}
+		}
+
+		return nil
+	}
+}
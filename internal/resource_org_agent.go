@@ -0,0 +1,292 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type orgAgentResource struct {
+	client woodpecker.Client
+}
+
+var _ resource.Resource = (*orgAgentResource)(nil)
+var _ resource.ResourceWithConfigure = (*orgAgentResource)(nil)
+var _ resource.ResourceWithImportState = (*orgAgentResource)(nil)
+
+func newOrgAgentResource() resource.Resource {
+	return &orgAgentResource{}
+}
+
+func (r *orgAgentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_org_agent"
+}
+
+func (r *orgAgentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This resource allows you to register/remove agents" +
+			" that only run workflows of repositories in specific organizations." +
+			" When applied, a new agent will be registered and its token will be available in the token attribute." +
+			" When destroyed, that agent will be removed." +
+			" For more information see" +
+			" [the Woodpecker docs](https://woodpecker-ci.org/docs/administration/configuration/agent).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the agent's id",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"org_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the organization",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "the name of the agent",
+			},
+			"no_schedule": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "whether new tasks should not be scheduled on this agent",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"custom_labels": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Description: "custom labels assigned to the agent, used to filter the tasks it picks up",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"token": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "the token the agent uses to authenticate with the server",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"owner_id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the ID of the user who registered the agent",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"platform": schema.StringAttribute{
+				Computed:    true,
+				Description: "the platform reported by the agent (e.g. linux/amd64)",
+			},
+			"backend": schema.StringAttribute{
+				Computed:    true,
+				Description: "the backend reported by the agent (e.g. docker, kubernetes, local)",
+			},
+			"version": schema.StringAttribute{
+				Computed:    true,
+				Description: "the version reported by the agent",
+			},
+			"capacity": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the number of workflows the agent can run in parallel",
+			},
+			"last_contact": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the time the agent last contacted the server (unix timestamp)",
+			},
+		},
+	}
+}
+
+func (r *orgAgentResource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *orgAgentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data orgAgentResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wData, diags := data.toWoodpeckerModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.client.WithContext(ctx).OrgAgentCreate(data.OrgID.ValueInt64(), wData)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't create agent", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, agent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *orgAgentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data orgAgentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.findAgent(ctx, data.OrgID.ValueInt64(), data.ID.ValueInt64())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get agent", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, agent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *orgAgentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data orgAgentResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wData, diags := data.toWoodpeckerModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	agent, err := r.client.WithContext(ctx).OrgAgentUpdate(data.OrgID.ValueInt64(), wData)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't update agent", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, agent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *orgAgentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data orgAgentResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.WithContext(ctx).OrgAgentDelete(data.OrgID.ValueInt64(), data.ID.ValueInt64()); err != nil {
+		resp.Diagnostics.AddError("Couldn't delete agent", err.Error())
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *orgAgentResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	idParts := strings.Split(req.ID, importStateIDSeparator)
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: org_id/id. Got: %q", req.ID),
+		)
+		return
+	}
+
+	orgID, err := strconv.ParseInt(idParts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid org id", err.Error())
+		return
+	}
+
+	id, err := strconv.ParseInt(idParts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid agent id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// findAgent looks for the agent with the given id in the organization's agent list,
+// there is no endpoint that returns a single organization agent.
+// It returns an error matching woodpecker.ErrNotFound if no such agent exists.
+func (r *orgAgentResource) findAgent(ctx context.Context, orgID, agentID int64) (*woodpecker.Agent, error) {
+	for agent, err := range woodpecker.Paginate(func(opt woodpecker.ListOptions) ([]*woodpecker.Agent, error) {
+		return r.client.WithContext(ctx).OrgAgentList(orgID, woodpecker.AgentListOptions{ListOptions: opt})
+	}) {
+		if err != nil {
+			return nil, err
+		}
+
+		if agent.ID == agentID {
+			return agent, nil
+		}
+	}
+
+	return nil, fmt.Errorf("agent with id '%d' %w in organization '%d'", agentID, woodpecker.ErrNotFound, orgID)
+}
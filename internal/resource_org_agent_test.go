@@ -0,0 +1,125 @@
+package internal_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestOrgAgentResource(t *testing.T) {
+	t.Parallel()
+
+	org := createOrg(t)
+	newOrg := createOrg(t)
+
+	name := uuid.NewString()
+	newName := uuid.NewString()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: checkOrgAgentResourceDestroy(map[int64][]string{
+			org.ID:    {name, newName},
+			newOrg.ID: {name, newName},
+		}),
+		Steps: []resource.TestStep{
+			{ // create agent
+				Config: fmt.Sprintf(`
+resource "woodpecker_org_agent" "test_agent" {
+	org_id = %d
+	name = "%s"
+}
+`, org.ID, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("woodpecker_org_agent.test_agent", "id"),
+					resource.TestCheckResourceAttr(
+						"woodpecker_org_agent.test_agent",
+						"org_id",
+						strconv.FormatInt(org.ID, 10),
+					),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "name", name),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "no_schedule", "false"),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "custom_labels.%", "0"),
+					resource.TestCheckResourceAttrSet("woodpecker_org_agent.test_agent", "token"),
+				),
+			},
+			{ // update agent
+				Config: fmt.Sprintf(`
+resource "woodpecker_org_agent" "test_agent" {
+	org_id = %d
+	name = "%s"
+	no_schedule = true
+	custom_labels = {
+		"location" = "europe"
+	}
+}
+`, org.ID, newName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("woodpecker_org_agent.test_agent", "id"),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "name", newName),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "no_schedule", "true"),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "custom_labels.%", "1"),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "custom_labels.location", "europe"),
+				),
+			},
+			{ // import
+				ResourceName:        "woodpecker_org_agent.test_agent",
+				ImportState:         true,
+				ImportStateIdPrefix: strconv.FormatInt(org.ID, 10) + "/",
+				ImportStateVerify:   true,
+			},
+			{ // replace agent
+				Config: fmt.Sprintf(`
+resource "woodpecker_org_agent" "test_agent" {
+	org_id = %d
+	name = "%s"
+}
+`, newOrg.ID, newName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(
+							"woodpecker_org_agent.test_agent",
+							plancheck.ResourceActionReplace,
+						),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("woodpecker_org_agent.test_agent", "id"),
+					resource.TestCheckResourceAttr(
+						"woodpecker_org_agent.test_agent",
+						"org_id",
+						strconv.FormatInt(newOrg.ID, 10),
+					),
+					resource.TestCheckResourceAttr("woodpecker_org_agent.test_agent", "name", newName),
+				),
+			},
+		},
+	})
+}
+
+func checkOrgAgentResourceDestroy(m map[int64][]string) func(state *terraform.State) error {
+	return func(_ *terraform.State) error {
+		for orgID, names := range m {
+			agents, err := woodpeckerClient.OrgAgentList(orgID, woodpecker.AgentListOptions{})
+			if err != nil {
+				return fmt.Errorf("couldn't list agents: %w", err)
+			}
+
+			if slices.ContainsFunc(agents, func(agent *woodpecker.Agent) bool {
+				return slices.Contains(names, agent.Name)
+			}) {
+				return errors.New("at least one of the created agents isn't deleted")
+			}
+		}
+
+		return nil
+	}
+}
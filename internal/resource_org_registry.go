@@ -0,0 +1,308 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type orgRegistryResource struct {
+	client woodpecker.Client
+}
+
+var _ resource.Resource = (*orgRegistryResource)(nil)
+var _ resource.ResourceWithConfigure = (*orgRegistryResource)(nil)
+var _ resource.ResourceWithConfigValidators = (*orgRegistryResource)(nil)
+var _ resource.ResourceWithImportState = (*orgRegistryResource)(nil)
+
+func newOrgRegistryResource() resource.Resource {
+	return &orgRegistryResource{}
+}
+
+func (r *orgRegistryResource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_org_registry"
+}
+
+func (r *orgRegistryResource) Schema(
+	_ context.Context,
+	_ resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This resource allows you to add/remove container registries" +
+			" that are available to all repositories in specific organizations." +
+			" When applied, a new registry will be created." +
+			" When destroyed, that registry will be removed." +
+			" For more information see [the Woodpecker docs](https://woodpecker-ci.org/docs/usage/registries).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the id of the registry",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"org_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the organization",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"address": schema.StringAttribute{
+				Required:    true,
+				Description: "the address of the registry (e.g. docker.io)",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Required:    true,
+				Description: "username used for authentication",
+			},
+			"password": schema.StringAttribute{
+				Optional: true,
+				Description: "password used for authentication. Stored in state; use password_wo to avoid that." +
+					" Conflicts with password_wo.",
+				Sensitive: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"password_wo": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Description: "password used for authentication, supplied as a write-only attribute so it's never " +
+					"persisted in state. Conflicts with password. Requires Terraform 1.11+ or OpenTofu 1.11+. " +
+					"Pair with password_wo_version to push new values.",
+			},
+			"password_wo_version": schema.Int64Attribute{
+				Optional: true,
+				Description: "the version of password_wo. Since write-only values aren't stored in state, " +
+					"increment this whenever password_wo changes to push the new value to Woodpecker.",
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("password_wo")),
+				},
+			},
+		},
+	}
+}
+
+func (r *orgRegistryResource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *orgRegistryResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("password"),
+			path.MatchRoot("password_wo"),
+		),
+	}
+}
+
+func (r *orgRegistryResource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var data orgRegistryResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wData, diags := data.toWoodpeckerModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.WithContext(ctx).OrgRegistryCreate(data.OrgID.ValueInt64(), wData)
+	if errors.Is(err, woodpecker.ErrConflict) {
+		resp.Diagnostics.AddError(
+			"Registry already exists",
+			fmt.Sprintf(
+				"A registry with the address %q already exists, import it to manage it with Terraform: %s",
+				wData.Address,
+				err,
+			),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't create registry", err.Error())
+		return
+	}
+
+	// OrgRegistryCreate doesn't return ID
+	registry, err := r.client.WithContext(ctx).OrgRegistry(data.OrgID.ValueInt64(), wData.Address)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get registry", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, registry)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only values must never be persisted in state.
+	data.PasswordWO = types.StringNull()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *orgRegistryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data orgRegistryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry, err := r.client.WithContext(ctx).OrgRegistry(data.OrgID.ValueInt64(), data.Address.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get registry", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, registry)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *orgRegistryResource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var data orgRegistryResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only values are null in the plan; read password_wo from the config.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &data.PasswordWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	wData, diags := data.toWoodpeckerModel(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	registry, err := r.client.WithContext(ctx).OrgRegistryUpdate(data.OrgID.ValueInt64(), wData)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't update registry", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, registry)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Write-only values must never be persisted in state.
+	data.PasswordWO = types.StringNull()
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *orgRegistryResource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var data orgRegistryResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.WithContext(ctx).OrgRegistryDelete(data.OrgID.ValueInt64(), data.Address.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't delete registry", err.Error())
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *orgRegistryResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	idParts := strings.Split(req.ID, importStateIDSeparator)
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: org_id/address. Got: %q", req.ID),
+		)
+		return
+	}
+
+	orgID, err := strconv.ParseInt(idParts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid org id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("org_id"), orgID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("address"), idParts[1])...)
+}
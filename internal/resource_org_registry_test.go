@@ -0,0 +1,258 @@
+package internal_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestOrgRegistryResource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		org := createOrg(t)
+		newOrg := createOrg(t)
+
+		address := fmt.Sprintf("%s.localhost", uuid.NewString())
+		newAddress := fmt.Sprintf("%s.localhost", uuid.NewString())
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy: checkOrgRegistryResourceDestroy(map[int64][]string{
+				org.ID:    {address, newAddress},
+				newOrg.ID: {address, newAddress},
+			}),
+			Steps: []resource.TestStep{
+				{ // create registry
+					Config: fmt.Sprintf(`
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = %d
+	address = "%s"
+	username = "test"
+	password = "test"
+}
+`, org.ID, address),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_org_registry.test_registry", "id"),
+						resource.TestCheckResourceAttr(
+							"woodpecker_org_registry.test_registry",
+							"org_id",
+							strconv.FormatInt(org.ID, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "address", address),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "username", "test"),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "password", "test"),
+					),
+				},
+				{ // update registry
+					Config: fmt.Sprintf(`
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = %d
+	address = "%s"
+	username = "test2"
+	password = "test2"
+}
+`, org.ID, address),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_org_registry.test_registry", "id"),
+						resource.TestCheckResourceAttr(
+							"woodpecker_org_registry.test_registry",
+							"org_id",
+							strconv.FormatInt(org.ID, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "address", address),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "username", "test2"),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "password", "test2"),
+					),
+				},
+				{ // import
+					ResourceName:            "woodpecker_org_registry.test_registry",
+					ImportState:             true,
+					ImportStateId:           fmt.Sprintf("%d/%s", org.ID, address),
+					ImportStateVerify:       true,
+					ImportStateVerifyIgnore: []string{"password"},
+				},
+				{ // replace registry
+					Config: fmt.Sprintf(`
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = %d
+	address = "%s"
+	username = "test"
+	password = "test"
+}
+`, org.ID, newAddress),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_org_registry.test_registry",
+								plancheck.ResourceActionReplace,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_org_registry.test_registry", "id"),
+						resource.TestCheckResourceAttr(
+							"woodpecker_org_registry.test_registry",
+							"org_id",
+							strconv.FormatInt(org.ID, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "address", newAddress),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "username", "test"),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "password", "test"),
+					),
+				},
+				{ // replace registry
+					Config: fmt.Sprintf(`
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = %d
+	address = "%s"
+	username = "test"
+	password = "test"
+}
+`, newOrg.ID, newAddress),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_org_registry.test_registry",
+								plancheck.ResourceActionReplace,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_org_registry.test_registry", "id"),
+						resource.TestCheckResourceAttr(
+							"woodpecker_org_registry.test_registry",
+							"org_id",
+							strconv.FormatInt(newOrg.ID, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "address", newAddress),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "username", "test"),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "password", "test"),
+					),
+				},
+			},
+		})
+	})
+
+}
+
+func TestOrgRegistryResourceWriteOnly(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		org := createOrg(t)
+
+		address := fmt.Sprintf("%s.localhost", uuid.NewString())
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+				tfversion.SkipBelow(tfversion.Version1_11_0),
+			},
+			CheckDestroy: checkOrgRegistryResourceDestroy(map[int64][]string{
+				org.ID: {address},
+			}),
+			Steps: []resource.TestStep{
+				{ // create registry with a write-only password
+					Config: fmt.Sprintf(`
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = %d
+	address = "%s"
+	username = "test"
+	password_wo = "test"
+	password_wo_version = 1
+}
+`, org.ID, address),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_org_registry.test_registry", "id"),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "address", address),
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "password_wo_version", "1"),
+						resource.TestCheckNoResourceAttr("woodpecker_org_registry.test_registry", "password"),
+						resource.TestCheckNoResourceAttr("woodpecker_org_registry.test_registry", "password_wo"),
+					),
+				},
+				{ // rotate the write-only password by bumping its version (in-place update)
+					Config: fmt.Sprintf(`
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = %d
+	address = "%s"
+	username = "test"
+	password_wo = "test2"
+	password_wo_version = 2
+}
+`, org.ID, address),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_org_registry.test_registry",
+								plancheck.ResourceActionUpdate,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_org_registry.test_registry", "password_wo_version", "2"),
+						resource.TestCheckNoResourceAttr("woodpecker_org_registry.test_registry", "password_wo"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: password and password_wo are mutually exclusive", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+resource "woodpecker_org_registry" "test_registry" {
+	org_id = 123
+	address = "docker.io"
+	username = "test"
+	password = "test"
+	password_wo = "test"
+}
+`,
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+			},
+		})
+	})
+}
+
+func checkOrgRegistryResourceDestroy(m map[int64][]string) func(state *terraform.State) error {
+	return func(_ *terraform.State) error {
+		for orgID, addresses := range m {
+			registries, err := woodpeckerClient.OrgRegistryList(orgID, woodpecker.RegistryListOptions{})
+			if err != nil {
+				return fmt.Errorf("couldn't list registries: %w", err)
+			}
+
+			if slices.ContainsFunc(registries, func(registry *woodpecker.Registry) bool {
+				return slices.Contains(addresses, registry.Address)
+			}) {
+				return errors.New("at least one of the created registries isn't deleted")
+			}
+		}
+
+		return nil
+	}
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -185,7 +186,14 @@ func (r *orgSecretResource) Create(
 		return
 	}
 
-	secret, err := r.client.OrgSecretCreate(data.OrgID.ValueInt64(), wData)
+	secret, err := r.client.WithContext(ctx).OrgSecretCreate(data.OrgID.ValueInt64(), wData)
+	if errors.Is(err, woodpecker.ErrConflict) {
+		resp.Diagnostics.AddError(
+			"Secret already exists",
+			fmt.Sprintf("A secret with the name %q already exists, import it to manage it with Terraform: %s", wData.Name, err),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't create secret", err.Error())
 		return
@@ -210,9 +218,14 @@ func (r *orgSecretResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	secret, err := r.client.OrgSecret(data.OrgID.ValueInt64(), data.Name.ValueString())
+	secret, err := r.client.WithContext(ctx).OrgSecret(data.OrgID.ValueInt64(), data.Name.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get secret", err.Error())
+		return
 	}
 
 	resp.Diagnostics.Append(data.setValues(ctx, secret)...)
@@ -247,7 +260,7 @@ func (r *orgSecretResource) Update(
 		return
 	}
 
-	secret, err := r.client.OrgSecretUpdate(data.OrgID.ValueInt64(), wData)
+	secret, err := r.client.WithContext(ctx).OrgSecretUpdate(data.OrgID.ValueInt64(), wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update secret", err.Error())
 		return
@@ -276,7 +289,7 @@ func (r *orgSecretResource) Delete(
 		return
 	}
 
-	if err := r.client.OrgSecretDelete(data.OrgID.ValueInt64(), data.Name.ValueString()); err != nil {
+	if err := r.client.WithContext(ctx).OrgSecretDelete(data.OrgID.ValueInt64(), data.Name.ValueString()); err != nil {
 		resp.Diagnostics.AddError("Couldn't delete secret", err.Error())
 		return
 	}
@@ -178,6 +178,82 @@ resource "woodpecker_org_secret" "test_secret" {
 		})
 	})
 
+	t.Run("OK: recreate secret deleted outside of terraform", func(t *testing.T) {
+		t.Parallel()
+
+		org := createOrg(t)
+
+		name := uuid.NewString()
+		cfg := fmt.Sprintf(`
+resource "woodpecker_org_secret" "test_secret" {
+	org_id = %d
+	name = "%s"
+	value = "test123"
+	events = ["%s"]
+}
+`, org.ID, name, woodpecker.EventPush)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkOrgSecretResourceDestroy(map[int64][]string{org.ID: {name}}),
+			Steps: []resource.TestStep{
+				{
+					Config: cfg,
+				},
+				{
+					PreConfig: func() {
+						if err := woodpeckerClient.OrgSecretDelete(org.ID, name); err != nil {
+							t.Fatal(err)
+						}
+					},
+					Config: cfg,
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("woodpecker_org_secret.test_secret", plancheck.ResourceActionCreate),
+						},
+					},
+					Check: resource.TestCheckResourceAttr("woodpecker_org_secret.test_secret", "name", name),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: secret already exists", func(t *testing.T) {
+		t.Parallel()
+
+		org := createOrg(t)
+
+		name := uuid.NewString()
+
+		if _, err := woodpeckerClient.OrgSecretCreate(org.ID, &woodpecker.Secret{
+			Name:   name,
+			Value:  "test123",
+			Events: []string{woodpecker.EventPush},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_org_secret" "test_secret" {
+	org_id = %d
+	name = "%s"
+	value = "test123"
+	events = ["%s"]
+}
+`, org.ID, name, woodpecker.EventPush),
+					// Woodpecker may report a duplicate secret as an internal error rather than a conflict
+					ExpectError: regexp.MustCompile(`Secret already exists|Couldn't create secret`),
+				},
+			},
+		})
+	})
+
 	t.Run("ERR: incorrect event value", func(t *testing.T) {
 		t.Parallel()
 
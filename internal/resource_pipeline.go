@@ -0,0 +1,488 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// pipelineDefaultTimeout is used when the timeout attribute isn't set (in minutes).
+	pipelineDefaultTimeout = 60
+	// pipelinePollInterval is how often the pipeline status is checked while waiting for completion.
+	pipelinePollInterval = 5 * time.Second
+	// pipelineFailureLogTail is how many log lines of each failed step are shown when a pipeline fails.
+	pipelineFailureLogTail = 20
+)
+
+type pipelineResource struct {
+	client woodpecker.Client
+}
+
+var _ resource.Resource = (*pipelineResource)(nil)
+var _ resource.ResourceWithConfigure = (*pipelineResource)(nil)
+var _ resource.ResourceWithImportState = (*pipelineResource)(nil)
+
+func newPipelineResource() resource.Resource {
+	return &pipelineResource{}
+}
+
+func (r *pipelineResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pipeline"
+}
+
+func (r *pipelineResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "This resource allows you to trigger manual pipelines." +
+			" When applied, a new pipeline will be started and, if `wait_for_completion` is set," +
+			" the apply will wait until it finishes and fail if it ends in failure, error or killed." +
+			" A new pipeline is started whenever `branch`, `variables` or `triggers` change." +
+			" When destroyed, the pipeline will be stopped if it's still running" +
+			" (finished pipelines are kept in the history)." +
+			" For more information see" +
+			" [the Woodpecker docs](https://woodpecker-ci.org/docs/usage/workflow-syntax#event-manual).",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the id of the pipeline",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"repository_id": schema.Int64Attribute{
+				Required:    true,
+				Description: "the ID of the repository",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"branch": schema.StringAttribute{
+				Required:    true,
+				Description: "the branch to run the pipeline on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"variables": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "custom variables passed to the pipeline",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "arbitrary map of values that, when changed, will start a new pipeline",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"wait_for_completion": schema.BoolAttribute{
+				Optional:    true,
+				Description: "whether to wait until the pipeline finishes, defaults to false",
+			},
+			"timeout": schema.Int64Attribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"how long to wait for the pipeline to finish (in minutes), defaults to %d",
+					pipelineDefaultTimeout,
+				),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"number": schema.Int64Attribute{
+				Computed:    true,
+				Description: "the number of the pipeline",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "the status of the pipeline",
+			},
+			"commit": schema.StringAttribute{
+				Computed:    true,
+				Description: "the commit SHA the pipeline runs on",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"workflows": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "the workflows of the pipeline",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the id of the workflow",
+						},
+						"pid": schema.Int64Attribute{
+							Computed:    true,
+							Description: "the process id of the workflow within the pipeline",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "the name of the workflow",
+						},
+						"state": schema.StringAttribute{
+							Computed:    true,
+							Description: "the state of the workflow",
+						},
+						"error": schema.StringAttribute{
+							Computed:    true,
+							Description: "the error the workflow failed with",
+						},
+						"started": schema.Int64Attribute{
+							Computed:    true,
+							Description: "date the workflow was started",
+						},
+						"stopped": schema.Int64Attribute{
+							Computed:    true,
+							Description: "date the workflow was stopped",
+						},
+						"steps": schema.ListNestedAttribute{
+							Computed:    true,
+							Description: "the steps of the workflow",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"id": schema.Int64Attribute{
+										Computed:    true,
+										Description: "the id of the step",
+									},
+									"pid": schema.Int64Attribute{
+										Computed:    true,
+										Description: "the process id of the step within the pipeline",
+									},
+									"name": schema.StringAttribute{
+										Computed:    true,
+										Description: "the name of the step",
+									},
+									"type": schema.StringAttribute{
+										Computed:    true,
+										Description: "the type of the step",
+									},
+									"state": schema.StringAttribute{
+										Computed:    true,
+										Description: "the state of the step",
+									},
+									"error": schema.StringAttribute{
+										Computed:    true,
+										Description: "the error the step failed with",
+									},
+									"exit_code": schema.Int64Attribute{
+										Computed:    true,
+										Description: "the exit code of the step",
+									},
+									"started": schema.Int64Attribute{
+										Computed:    true,
+										Description: "date the step was started",
+									},
+									"stopped": schema.Int64Attribute{
+										Computed:    true,
+										Description: "date the step was stopped",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *pipelineResource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	resp *resource.ConfigureResponse,
+) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(woodpecker.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf(
+				"Expected woodpecker.Client, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData,
+			),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+func (r *pipelineResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data pipelineResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts, diags := data.toWoodpeckerOptions(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pipeline, err := r.client.WithContext(ctx).PipelineCreate(data.RepositoryID.ValueInt64(), opts)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't create pipeline", err.Error())
+		return
+	}
+
+	if data.WaitForCompletion.ValueBool() {
+		var finished *woodpecker.Pipeline
+		finished, err = waitForPipeline(ctx, r.client, data.RepositoryID.ValueInt64(), pipeline.Number, data.timeout())
+		if finished != nil {
+			pipeline = finished
+		}
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, pipeline)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the state is saved even if the pipeline failed, so that Terraform marks the resource as tainted
+	// and starts a new pipeline on the next apply
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't wait for pipeline", err.Error())
+		return
+	}
+
+	if data.WaitForCompletion.ValueBool() && isPipelineFailed(pipeline.Status) {
+		resp.Diagnostics.AddError(
+			"Pipeline failed",
+			fmt.Sprintf("Pipeline #%d finished with status %q", pipeline.Number, pipeline.Status)+
+				failedStepsLog(ctx, r.client, data.RepositoryID.ValueInt64(), pipeline),
+		)
+	}
+}
+
+func (r *pipelineResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data pipelineResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pipeline, err := r.client.WithContext(ctx).Pipeline(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get pipeline", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, pipeline)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *pipelineResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data pipelineResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only wait_for_completion and timeout can be updated in place,
+	// they don't affect the pipeline that has already been started
+	pipeline, err := r.client.WithContext(ctx).Pipeline(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get pipeline", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(data.setValues(ctx, pipeline)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *pipelineResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data pipelineResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pipeline, err := r.client.WithContext(ctx).Pipeline(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't get pipeline", err.Error())
+		return
+	}
+
+	if !isPipelineFinished(pipeline.Status) {
+		err = r.client.WithContext(ctx).PipelineStop(data.RepositoryID.ValueInt64(), data.Number.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't stop pipeline", err.Error())
+			return
+		}
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *pipelineResource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	idParts := strings.Split(req.ID, importStateIDSeparator)
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected import identifier with format: repository_id/number. Got: %q", req.ID),
+		)
+		return
+	}
+
+	repoID, err := strconv.ParseInt(idParts[0], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid repository id", err.Error())
+		return
+	}
+
+	number, err := strconv.ParseInt(idParts[1], 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid pipeline number", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("repository_id"), repoID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("number"), number)...)
+}
+
+// waitForPipeline polls the pipeline until it finishes or the timeout expires.
+// On timeout, it returns the last fetched pipeline along with the error.
+func waitForPipeline(
+	ctx context.Context,
+	client woodpecker.Client,
+	repoID, number int64,
+	timeout time.Duration,
+) (*woodpecker.Pipeline, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pipelinePollInterval)
+	defer ticker.Stop()
+
+	var pipeline *woodpecker.Pipeline
+	for {
+		p, err := client.WithContext(ctx).Pipeline(repoID, number)
+		if err != nil && ctx.Err() == nil {
+			return pipeline, err
+		}
+		if err == nil {
+			pipeline = p
+			if isPipelineFinished(pipeline.Status) {
+				return pipeline, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return pipeline, fmt.Errorf("pipeline #%d didn't finish within %s", number, timeout)
+			}
+			return pipeline, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// failedStepsLog returns the tail of the log of each failed step, formatted for diagnostics.
+// Logs are fetched on a best-effort basis, steps whose log can't be fetched are skipped.
+func failedStepsLog(
+	ctx context.Context,
+	client woodpecker.Client,
+	repoID int64,
+	pipeline *woodpecker.Pipeline,
+) string {
+	var sb strings.Builder
+	for _, workflow := range pipeline.Workflows {
+		for _, step := range workflow.Children {
+			if !isPipelineFailed(step.State) {
+				continue
+			}
+
+			entries, err := client.WithContext(ctx).StepLogEntries(repoID, pipeline.Number, step.ID)
+			if err != nil {
+				continue
+			}
+
+			fmt.Fprintf(&sb, "\n\nStep %q (workflow %q) exited with code %d:\n", step.Name, workflow.Name, step.ExitCode)
+			sb.WriteString(woodpecker.DecodeLogEntries(entries, woodpecker.LogDecodeOptions{
+				Types: []woodpecker.LogEntryType{woodpecker.LogEntryStdout, woodpecker.LogEntryStderr},
+				Tail:  pipelineFailureLogTail,
+			}))
+		}
+	}
+	return sb.String()
+}
+
+func isPipelineFinished(status string) bool {
+	switch status {
+	case woodpecker.StatusSuccess,
+		woodpecker.StatusFailure,
+		woodpecker.StatusKilled,
+		woodpecker.StatusError,
+		woodpecker.StatusSkipped,
+		woodpecker.StatusDeclined:
+		return true
+	default:
+		return false
+	}
+}
+
+func isPipelineFailed(status string) bool {
+	switch status {
+	case woodpecker.StatusFailure, woodpecker.StatusError, woodpecker.StatusKilled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,294 @@
+package internal_test
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestPipelineResource(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkPipelineResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{ // start pipeline
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	branch = "%s"
+	variables = {
+		FOO = "bar"
+	}
+	triggers = {
+		version = "1"
+	}
+}
+`, repo.ID, giteaRepo.DefaultBranch),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_pipeline.test_pipeline", "id"),
+						resource.TestCheckResourceAttr(
+							"woodpecker_pipeline.test_pipeline",
+							"repository_id",
+							strconv.FormatInt(repo.ID, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_pipeline.test_pipeline", "branch", giteaRepo.DefaultBranch),
+						resource.TestCheckResourceAttr("woodpecker_pipeline.test_pipeline", "number", "1"),
+						resource.TestCheckResourceAttrSet("woodpecker_pipeline.test_pipeline", "status"),
+						resource.TestCheckResourceAttrSet("woodpecker_pipeline.test_pipeline", "commit"),
+					),
+				},
+				{ // update wait settings in place
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	branch = "%s"
+	variables = {
+		FOO = "bar"
+	}
+	triggers = {
+		version = "1"
+	}
+	timeout = 5
+}
+`, repo.ID, giteaRepo.DefaultBranch),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("woodpecker_pipeline.test_pipeline", plancheck.ResourceActionUpdate),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_pipeline.test_pipeline", "number", "1"),
+						resource.TestCheckResourceAttr("woodpecker_pipeline.test_pipeline", "timeout", "5"),
+					),
+				},
+				{ // start new pipeline
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	branch = "%s"
+	variables = {
+		FOO = "bar"
+	}
+	triggers = {
+		version = "2"
+	}
+	timeout = 5
+}
+`, repo.ID, giteaRepo.DefaultBranch),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_pipeline.test_pipeline",
+								plancheck.ResourceActionDestroyBeforeCreate,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_pipeline.test_pipeline", "number", "2"),
+					),
+				},
+				{ // import
+					ResourceName:      "woodpecker_pipeline.test_pipeline",
+					ImportState:       true,
+					ImportStateVerify: true,
+					ImportStateVerifyIgnore: []string{
+						"variables",
+						"triggers",
+						"timeout",
+						"status",
+						"workflows",
+					},
+					ImportStateIdFunc: func(state *terraform.State) (string, error) {
+						rs, ok := state.RootModule().Resources["woodpecker_pipeline.test_pipeline"]
+						if !ok {
+							return "", errors.New("woodpecker_pipeline.test_pipeline not found in state")
+						}
+						return rs.Primary.Attributes["repository_id"] + "/" + rs.Primary.Attributes["number"], nil
+					},
+				},
+			},
+		})
+	})
+
+	t.Run("OK: wait for completion", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		finishPipelines(t, repo, woodpecker.StatusSuccess)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkPipelineResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	branch = "%s"
+	wait_for_completion = true
+}
+`, repo.ID, giteaRepo.DefaultBranch),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_pipeline.test_pipeline", "number", "1"),
+						resource.TestCheckResourceAttr("woodpecker_pipeline.test_pipeline", "status", woodpecker.StatusSuccess),
+						resource.TestCheckResourceAttr(
+							"woodpecker_pipeline.test_pipeline",
+							"workflows.0.steps.1.state",
+							woodpecker.StatusSuccess,
+						),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: pipeline failed", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		finishPipelines(t, repo, woodpecker.StatusFailure, logLines(25)...)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkPipelineResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	branch = "%s"
+	wait_for_completion = true
+}
+`, repo.ID, giteaRepo.DefaultBranch),
+					// only the last 20 lines of the failed step are included
+					ExpectError: regexp.MustCompile(
+						`(?s)Pipeline failed.*Pipeline #1 finished with status "failure".*` +
+							`Step "build" \(workflow "woodpecker"\) exited with code 1:[\s│]+line 6[\s│]+line 7[\s│].*line 25`,
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: pipeline killed", func(t *testing.T) {
+		t.Parallel()
+
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+		finishPipelines(t, repo, woodpecker.StatusKilled)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkPipelineResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	branch = "%s"
+	wait_for_completion = true
+}
+`, repo.ID, giteaRepo.DefaultBranch),
+					ExpectError: regexp.MustCompile(`(?s)Pipeline failed.*Pipeline #1 finished with status "killed"`),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: timeout", func(t *testing.T) {
+		t.Parallel()
+
+		// there is no agent, the pipeline stays pending
+		giteaRepo := createRepo(t)
+		createPipelineConfig(t, giteaRepo)
+		repo := activateRepo(t, giteaRepo)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkPipelineResourceDestroy(repo.ID),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = %d
+	branch = "%s"
+	wait_for_completion = true
+	timeout = 1
+}
+`, repo.ID, giteaRepo.DefaultBranch),
+					ExpectError: regexp.MustCompile(
+						`(?s)Couldn't wait for pipeline.*pipeline #1 didn't finish within 1m0s`,
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: incorrect timeout value", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_pipeline" "test_pipeline" {
+	repository_id = 1
+	branch = "%s"
+	timeout = 0
+}
+`, uuid.NewString()),
+					ExpectError: regexp.MustCompile(`Attribute timeout value must be at least 1`),
+				},
+			},
+		})
+	})
+}
+
+func checkPipelineResourceDestroy(repoID int64) func(state *terraform.State) error {
+	return func(_ *terraform.State) error {
+		pipelines, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Pipeline, error) {
+			return woodpeckerClient.PipelineList(repoID, woodpecker.PipelineListOptions{ListOptions: opt})
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't list pipelines: %w", err)
+		}
+
+		for _, pipeline := range pipelines {
+			switch pipeline.Status {
+			case woodpecker.StatusPending, woodpecker.StatusRunning:
+				return fmt.Errorf("pipeline #%d is still %s", pipeline.Number, pipeline.Status)
+			}
+		}
+
+		return nil
+	}
+}
@@ -2,13 +2,16 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"slices"
+	"strings"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Masterminds/semver/v3"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -22,6 +25,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
+// repositoryVersionedSettingsMinVersions holds the minimum Woodpecker version
+// that supports each of the settings returned by repositoryModel.versionedSettings.
+var repositoryVersionedSettingsMinVersions = map[string]string{
+	"config_extension_endpoint":   "3.0.0",
+	"registry_extension_endpoint": "3.8.0",
+	"secret_extension_endpoint":   "3.8.0",
+	"max_pipeline_age":            "3.8.0",
+	"log_expiry":                  "3.8.0",
+}
+
+func repositoryVersionedSettingDescription(name string) string {
+	return fmt.Sprintf("Requires Woodpecker %s or later.", repositoryVersionedSettingsMinVersions[name])
+}
+
 type repositoryResource struct {
 	client woodpecker.Client
 }
@@ -44,7 +61,13 @@ func (r *repositoryResource) Metadata(
 
 func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: `Provides a repository resource.`,
+		MarkdownDescription: "Provides a repository resource." +
+			"\n\nIf `forge_remote_id` is set, the repository is activated directly." +
+			" Otherwise, it's looked up by `full_name` in Woodpecker and, if Woodpecker doesn't know it" +
+			" (i.e. it has never been activated), in the list of all repositories the current user has access to" +
+			" on the forge. Listing all repositories can be slow for users with access to many repositories," +
+			" set `forge_remote_id` to avoid it. The same lookup is done when `full_name` changes," +
+			" to check whether the new name refers to the same forge repository.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
 				Computed:    true,
@@ -54,16 +77,26 @@ func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				},
 			},
 			"forge_id": schema.Int64Attribute{
-				Computed:    true,
-				Description: "the forge's id",
+				Optional: true,
+				Computed: true,
+				Description: "the forge's id. It can be set together with forge_remote_id " +
+					"to activate a repository from a specific forge, by default the forge of the current user is used.",
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("forge_remote_id")),
+				},
 				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplaceIfConfigured(),
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
 			"forge_remote_id": schema.StringAttribute{
-				Computed:    true,
-				Description: "the unique identifier for the repository on the forge",
+				Optional: true,
+				Computed: true,
+				Description: "the unique identifier for the repository on the forge. If it's set, " +
+					"the repository is activated directly, otherwise it's looked up by full_name, " +
+					"which may require listing all repositories available on the forge.",
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
@@ -72,6 +105,7 @@ func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "the URL of the repository on the forge",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					unknownOnMove(),
 				},
 			},
 			"clone_url": schema.StringAttribute{
@@ -79,6 +113,7 @@ func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "the URL to clone repository",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					unknownOnMove(),
 				},
 			},
 			"owner": schema.StringAttribute{
@@ -86,6 +121,7 @@ func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "the owner of the repository",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					unknownOnMove(),
 				},
 			},
 			"name": schema.StringAttribute{
@@ -93,13 +129,21 @@ func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "the name of the repository",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					unknownOnMove(),
 				},
 			},
 			"full_name": schema.StringAttribute{
-				Required:    true,
-				Description: "the full name of the repository (format: owner/reponame)",
+				Required: true,
+				Description: "the full name of the repository (format: owner/reponame). " +
+					"Changing it moves the repository in place if the new name refers to the same forge repository " +
+					"(e.g. after it has been renamed or transferred to another owner on the forge), " +
+					"otherwise the repository is replaced.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						r.requiresReplaceIfOtherForgeRepo,
+						"The repository will be replaced if the new full name refers to a different forge repository.",
+						"The repository will be replaced if the new full name refers to a different forge repository.",
+					),
 				},
 			},
 			"avatar_url": schema.StringAttribute{
@@ -107,6 +151,7 @@ func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "the repository's avatar URL",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					unknownOnMove(),
 				},
 			},
 			"default_branch": schema.StringAttribute{
@@ -289,6 +334,80 @@ func (r *repositoryResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					setplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"config_extension_endpoint": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "the URL of the config extension that is asked for the pipeline configuration" +
+					" instead of the forge. " + repositoryVersionedSettingDescription("config_extension_endpoint"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"registry_extension_endpoint": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "the URL of the extension that provides registries for pipelines of the repository. " +
+					repositoryVersionedSettingDescription("registry_extension_endpoint"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"secret_extension_endpoint": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "the URL of the extension that provides secrets for pipelines of the repository. " +
+					repositoryVersionedSettingDescription("secret_extension_endpoint"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"max_pipeline_age": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Description: "pipelines older than this are deleted (in days, 0 means the server default). " +
+					repositoryVersionedSettingDescription("max_pipeline_age"),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"log_expiry": schema.Int64Attribute{
+				Optional: true,
+				Computed: true,
+				Description: "logs of pipelines older than this are deleted (in days, 0 means the server default). " +
+					repositoryVersionedSettingDescription("log_expiry"),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"take_ownership": schema.BoolAttribute{
+				Optional: true,
+				Description: "Makes the current user the owner of the repository in Woodpecker. " +
+					"Pipelines use the owner's forge credentials, so this helps when the previous owner lost access.",
+			},
+			"delete_mode": schema.StringAttribute{
+				Optional: true,
+				Description: fmt.Sprintf(
+					"What happens to the repository on destroy. "+
+						"%s (default) keeps pipelines, secrets and other data, the repository is activated again on create. "+
+						"%s deletes the repository with all its data.",
+					repositoryDeleteModeDeactivate,
+					repositoryDeleteModeRemove,
+				),
+				Validators: []validator.String{
+					stringvalidator.OneOf(repositoryDeleteModeDeactivate, repositoryDeleteModeRemove),
+				},
+			},
+			"repair_trigger": schema.StringAttribute{
+				Optional: true,
+				Description: "An arbitrary value that, whenever it changes, " +
+					"makes Woodpecker repair the repository (e.g. re-create the forge webhook).",
+			},
 		},
 	}
 }
@@ -319,27 +438,36 @@ func (r *repositoryResource) Configure(
 }
 
 func (r *repositoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var data repositoryModel
+	var data repositoryResourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	repoFullName := data.FullName.ValueString()
-
-	repos, err := r.client.RepoList(woodpecker.RepoListOptions{All: true})
-	if err != nil {
-		resp.Diagnostics.AddError("Couldn't list repositories", err.Error())
+	resp.Diagnostics.Append(r.checkVersionedSettings(ctx, data.repositoryModel)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	idx := slices.IndexFunc(repos, func(repo *woodpecker.Repo) bool {
-		return repo.FullName == repoFullName
-	})
-	if idx < 0 {
-		resp.Diagnostics.AddError("Repository not found", fmt.Sprintf("Repository with name '%s' not found", repoFullName))
-		return
+	repoFullName := data.FullName.ValueString()
+
+	postOpts := woodpecker.RepoPostOptions{
+		ForgeRemoteID: data.ForgeRemoteID.ValueString(),
+		ForgeID:       data.ForgeID.ValueInt64(),
+	}
+	if postOpts.ForgeRemoteID == "" {
+		forgeRepo, err := r.client.WithContext(ctx).RepoForgeLookup(repoFullName)
+		if errors.Is(err, woodpecker.ErrNotFound) {
+			resp.Diagnostics.AddError("Repository not found", fmt.Sprintf("Repository with name '%s' not found", repoFullName))
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't look up repository", err.Error())
+			return
+		}
+		postOpts.ForgeRemoteID = forgeRepo.ForgeRemoteID
+		postOpts.ForgeID = forgeRepo.ForgeID
 	}
 
 	wData, diags := data.toWoodpeckerPatch(ctx)
@@ -348,15 +476,43 @@ func (r *repositoryResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	activatedRepo, err := r.client.RepoPost(woodpecker.RepoPostOptions{
-		ForgeRemoteID: repos[idx].ForgeRemoteID,
-	})
+	activatedRepo, err := r.client.WithContext(ctx).RepoPost(postOpts)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't activate repository", err.Error())
 		return
 	}
 
-	updatedRepo, err := r.client.RepoPatch(activatedRepo.ID, wData)
+	if !strings.EqualFold(activatedRepo.FullName, repoFullName) {
+		// the repository isn't tracked in state, so it's deactivated again, keeping its data
+		if err = r.client.WithContext(ctx).RepoDel(activatedRepo.ID, woodpecker.RepoDelOptions{
+			Remove: false,
+		}); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Couldn't deactivate repository",
+				fmt.Sprintf("Repository '%s' remains active: %s", activatedRepo.FullName, err),
+			)
+		}
+		resp.Diagnostics.AddAttributeError(
+			path.Root("forge_remote_id"),
+			"Repository name mismatch",
+			fmt.Sprintf(
+				"The activated repository is named '%s', but full_name is '%s'. "+
+					"Make sure forge_remote_id points to the right repository.",
+				activatedRepo.FullName,
+				repoFullName,
+			),
+		)
+		return
+	}
+
+	if data.TakeOwnership.ValueBool() {
+		if _, err = r.client.WithContext(ctx).RepoChown(activatedRepo.ID); err != nil {
+			resp.Diagnostics.AddError("Couldn't take ownership of repository", err.Error())
+			return
+		}
+	}
+
+	updatedRepo, err := r.client.WithContext(ctx).RepoPatch(activatedRepo.ID, wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update repository", err.Error())
 		return
@@ -371,16 +527,27 @@ func (r *repositoryResource) Create(ctx context.Context, req resource.CreateRequ
 }
 
 func (r *repositoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data repositoryModel
+	var data repositoryResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	repo, err := r.client.RepoLookup(data.FullName.ValueString())
+	repo, err := r.client.WithContext(ctx).RepoLookup(data.FullName.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get repository", err.Error())
+		return
+	}
+
+	// a deactivated repository is treated as deleted, it'll be activated again on the next apply
+	if !repo.IsActive {
+		resp.State.RemoveResource(ctx)
+		return
 	}
 
 	resp.Diagnostics.Append(data.setValues(ctx, repo)...)
@@ -392,20 +559,78 @@ func (r *repositoryResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *repositoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data repositoryModel
+	var data repositoryResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	var state repositoryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// computed settings are copied from the state to the plan, only the configured ones are checked
+	var config repositoryModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.checkVersionedSettings(ctx, config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repoID := data.ID.ValueInt64()
+
+	if !data.FullName.Equal(state.FullName) {
+		err := r.client.WithContext(ctx).RepoMove(repoID, woodpecker.RepoMoveOptions{To: data.FullName.ValueString()})
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't move repository", err.Error())
+			return
+		}
+
+		// each completed step is saved right away, so that the state matches the server if a later step fails
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("full_name"), data.FullName)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if data.TakeOwnership.ValueBool() && !state.TakeOwnership.ValueBool() {
+		if _, err := r.client.WithContext(ctx).RepoChown(repoID); err != nil {
+			resp.Diagnostics.AddError("Couldn't take ownership of repository", err.Error())
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("take_ownership"), data.TakeOwnership)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !data.RepairTrigger.IsNull() && !data.RepairTrigger.Equal(state.RepairTrigger) {
+		if err := r.client.WithContext(ctx).RepoRepair(repoID); err != nil {
+			resp.Diagnostics.AddError("Couldn't repair repository", err.Error())
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("repair_trigger"), data.RepairTrigger)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	wData, diags := data.toWoodpeckerPatch(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	repo, err := r.client.RepoPatch(data.ID.ValueInt64(), wData)
+	repo, err := r.client.WithContext(ctx).RepoPatch(repoID, wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update repository", err.Error())
 		return
@@ -420,14 +645,17 @@ func (r *repositoryResource) Update(ctx context.Context, req resource.UpdateRequ
 }
 
 func (r *repositoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var data repositoryModel
+	var data repositoryResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err := r.client.RepoDel(data.ID.ValueInt64()); err != nil {
+	err := r.client.WithContext(ctx).RepoDel(data.ID.ValueInt64(), woodpecker.RepoDelOptions{
+		Remove: data.deleteMode() == repositoryDeleteModeRemove,
+	})
+	if err != nil {
 		resp.Diagnostics.AddError("Couldn't delete repository", err.Error())
 		return
 	}
@@ -437,6 +665,49 @@ func (r *repositoryResource) Delete(ctx context.Context, req resource.DeleteRequ
 	// from provider logic.
 }
 
+// checkVersionedSettings returns an error for each configured setting the server doesn't support.
+// The server version is fetched only if at least one of such settings is configured.
+func (r *repositoryResource) checkVersionedSettings(ctx context.Context, config repositoryModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	names := config.versionedSettings()
+	if len(names) == 0 {
+		return diags
+	}
+
+	ver, err := r.client.WithContext(ctx).Version()
+	if err != nil {
+		diags.AddError("Couldn't get woodpecker version", err.Error())
+		return diags
+	}
+
+	parsedVer, err := parseWoodpeckerVersion(ver.Version)
+	if err != nil {
+		diags.AddError("Couldn't parse woodpecker version", err.Error())
+		return diags
+	}
+
+	for _, name := range names {
+		minVersion := repositoryVersionedSettingsMinVersions[name]
+		if !parsedVer.LessThan(semver.MustParse(minVersion)) {
+			continue
+		}
+
+		diags.AddAttributeError(
+			path.Root(name),
+			"Unsupported Woodpecker version",
+			fmt.Sprintf(
+				"%s requires Woodpecker %s or later, current version: %s.",
+				name,
+				minVersion,
+				ver.Version,
+			),
+		)
+	}
+
+	return diags
+}
+
 func (r *repositoryResource) ImportState(
 	ctx context.Context,
 	req resource.ImportStateRequest,
@@ -444,3 +715,83 @@ func (r *repositoryResource) ImportState(
 ) {
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("full_name"), req.ID)...)
 }
+
+// requiresReplaceIfOtherForgeRepo requires replacing the repository if the new full_name refers
+// to a different forge repository than the one in the state, only the same forge repository can be moved in place.
+// Woodpecker keeps the old name until the move, so the new name is usually found only in the list of forge
+// repositories, which the client loads once per provider run.
+func (r *repositoryResource) requiresReplaceIfOtherForgeRepo(
+	ctx context.Context,
+	req planmodifier.StringRequest,
+	resp *stringplanmodifier.RequiresReplaceIfFuncResponse,
+) {
+	if req.PlanValue.IsUnknown() || r.client == nil {
+		resp.RequiresReplace = true
+		return
+	}
+
+	var forgeRemoteID, configForgeRemoteID types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("forge_remote_id"), &forgeRemoteID)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("forge_remote_id"), &configForgeRemoteID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the configured forge_remote_id identifies the forge repository without the lookup,
+	// changing it requires replacement on its own
+	if !configForgeRemoteID.IsNull() && !configForgeRemoteID.IsUnknown() {
+		resp.RequiresReplace = configForgeRemoteID.ValueString() != forgeRemoteID.ValueString()
+		return
+	}
+
+	repo, err := r.client.WithContext(ctx).RepoForgeLookup(req.PlanValue.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		// the replacement fails with a proper error when the repository is activated
+		resp.RequiresReplace = true
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Couldn't look up repository", err.Error())
+		return
+	}
+
+	resp.RequiresReplace = repo.ForgeRemoteID != forgeRemoteID.ValueString()
+}
+
+// unknownOnMove returns a plan modifier that marks the value as unknown if full_name changes,
+// the attributes derived from the repository name are refreshed after the repository is moved.
+func unknownOnMove() planmodifier.String {
+	return unknownOnMoveModifier{}
+}
+
+type unknownOnMoveModifier struct{}
+
+func (m unknownOnMoveModifier) Description(_ context.Context) string {
+	return "The value will be recomputed if full_name changes."
+}
+
+func (m unknownOnMoveModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m unknownOnMoveModifier) PlanModifyString(
+	ctx context.Context,
+	req planmodifier.StringRequest,
+	resp *planmodifier.StringResponse,
+) {
+	// nothing to do on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned, current types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("full_name"), &planned)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("full_name"), &current)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !planned.Equal(current) {
+		resp.PlanValue = types.StringUnknown()
+	}
+}
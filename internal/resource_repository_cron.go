@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -133,7 +134,18 @@ func (r *repositoryCronResource) Create(
 		return
 	}
 
-	cron, err := r.client.CronCreate(data.RepositoryID.ValueInt64(), wData)
+	cron, err := r.client.WithContext(ctx).CronCreate(data.RepositoryID.ValueInt64(), wData)
+	if errors.Is(err, woodpecker.ErrConflict) {
+		resp.Diagnostics.AddError(
+			"Cron job already exists",
+			fmt.Sprintf(
+				"A cron job with the name %q already exists, import it to manage it with Terraform: %s",
+				wData.Name,
+				err,
+			),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't create cron job", err.Error())
 		return
@@ -155,9 +167,14 @@ func (r *repositoryCronResource) Read(ctx context.Context, req resource.ReadRequ
 		return
 	}
 
-	cron, err := r.client.CronGet(data.RepositoryID.ValueInt64(), data.ID.ValueInt64())
+	cron, err := r.client.WithContext(ctx).CronGet(data.RepositoryID.ValueInt64(), data.ID.ValueInt64())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get cron job", err.Error())
+		return
 	}
 
 	resp.Diagnostics.Append(data.setValues(ctx, cron)...)
@@ -186,7 +203,7 @@ func (r *repositoryCronResource) Update(
 		return
 	}
 
-	cron, err := r.client.CronUpdate(data.RepositoryID.ValueInt64(), wData)
+	cron, err := r.client.WithContext(ctx).CronUpdate(data.RepositoryID.ValueInt64(), wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update cron job", err.Error())
 		return
@@ -212,7 +229,7 @@ func (r *repositoryCronResource) Delete(
 		return
 	}
 
-	if err := r.client.CronDelete(data.RepositoryID.ValueInt64(), data.ID.ValueInt64()); err != nil {
+	if err := r.client.WithContext(ctx).CronDelete(data.RepositoryID.ValueInt64(), data.ID.ValueInt64()); err != nil {
 		resp.Diagnostics.AddError("Couldn't delete cron job", err.Error())
 		return
 	}
@@ -2,17 +2,22 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 type repositoryRegistryResource struct {
@@ -21,7 +26,9 @@ type repositoryRegistryResource struct {
 
 var _ resource.Resource = (*repositoryRegistryResource)(nil)
 var _ resource.ResourceWithConfigure = (*repositoryRegistryResource)(nil)
+var _ resource.ResourceWithConfigValidators = (*repositoryRegistryResource)(nil)
 var _ resource.ResourceWithImportState = (*repositoryRegistryResource)(nil)
+var _ resource.ResourceWithUpgradeState = (*repositoryRegistryResource)(nil)
 
 func newRepositoryRegistryResource() resource.Resource {
 	return &repositoryRegistryResource{}
@@ -41,6 +48,7 @@ func (r *repositoryRegistryResource) Schema(
 	resp *resource.SchemaResponse,
 ) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		MarkdownDescription: "This resource allows you to add/remove container registries for specific repositories." +
 			" When applied, a new registry will be created." +
 			" When destroyed, that registry will be removed." +
@@ -72,9 +80,29 @@ func (r *repositoryRegistryResource) Schema(
 				Description: "username used for authentication",
 			},
 			"password": schema.StringAttribute{
-				Required:    true,
-				Description: "password used for authentication",
-				Sensitive:   true,
+				Optional: true,
+				Description: "password used for authentication. Stored in state; use password_wo to avoid that." +
+					" Conflicts with password_wo.",
+				Sensitive: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"password_wo": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Description: "password used for authentication, supplied as a write-only attribute so it's never " +
+					"persisted in state. Conflicts with password. Requires Terraform 1.11+ or OpenTofu 1.11+. " +
+					"Pair with password_wo_version to push new values.",
+			},
+			"password_wo_version": schema.Int64Attribute{
+				Optional: true,
+				Description: "the version of password_wo. Since write-only values aren't stored in state, " +
+					"increment this whenever password_wo changes to push the new value to Woodpecker.",
+				Validators: []validator.Int64{
+					int64validator.AlsoRequires(path.MatchRoot("password_wo")),
+				},
 			},
 		},
 	}
@@ -105,12 +133,21 @@ func (r *repositoryRegistryResource) Configure(
 	r.client = client
 }
 
+func (r *repositoryRegistryResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("password"),
+			path.MatchRoot("password_wo"),
+		),
+	}
+}
+
 func (r *repositoryRegistryResource) Create(
 	ctx context.Context,
 	req resource.CreateRequest,
 	resp *resource.CreateResponse,
 ) {
-	var data repositoryRegistryResourceModel
+	var data repositoryRegistryResourceModelV1
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -123,14 +160,25 @@ func (r *repositoryRegistryResource) Create(
 		return
 	}
 
-	_, err := r.client.RegistryCreate(data.RepositoryID.ValueInt64(), wData)
+	_, err := r.client.WithContext(ctx).RegistryCreate(data.RepositoryID.ValueInt64(), wData)
+	if errors.Is(err, woodpecker.ErrConflict) {
+		resp.Diagnostics.AddError(
+			"Registry already exists",
+			fmt.Sprintf(
+				"A registry with the address %q already exists, import it to manage it with Terraform: %s",
+				wData.Address,
+				err,
+			),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't create registry", err.Error())
 		return
 	}
 
 	// RegistryCreate doesn't return ID
-	registry, err := r.client.Registry(data.RepositoryID.ValueInt64(), wData.Address)
+	registry, err := r.client.WithContext(ctx).Registry(data.RepositoryID.ValueInt64(), wData.Address)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get registry", err.Error())
 		return
@@ -141,20 +189,28 @@ func (r *repositoryRegistryResource) Create(
 		return
 	}
 
+	// Write-only values must never be persisted in state.
+	data.PasswordWO = types.StringNull()
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *repositoryRegistryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data repositoryRegistryResourceModel
+	var data repositoryRegistryResourceModelV1
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	registry, err := r.client.Registry(data.RepositoryID.ValueInt64(), data.Address.ValueString())
+	registry, err := r.client.WithContext(ctx).Registry(data.RepositoryID.ValueInt64(), data.Address.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get registry", err.Error())
+		return
 	}
 
 	resp.Diagnostics.Append(data.setValues(ctx, registry)...)
@@ -170,20 +226,26 @@ func (r *repositoryRegistryResource) Update(
 	req resource.UpdateRequest,
 	resp *resource.UpdateResponse,
 ) {
-	var data repositoryRegistryResourceModel
+	var data repositoryRegistryResourceModelV1
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Write-only values are null in the plan; read password_wo from the config.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &data.PasswordWO)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	wData, diags := data.toWoodpeckerModel(ctx)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	registry, err := r.client.RegistryUpdate(data.RepositoryID.ValueInt64(), wData)
+	registry, err := r.client.WithContext(ctx).RegistryUpdate(data.RepositoryID.ValueInt64(), wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update registry", err.Error())
 		return
@@ -194,6 +256,9 @@ func (r *repositoryRegistryResource) Update(
 		return
 	}
 
+	// Write-only values must never be persisted in state.
+	data.PasswordWO = types.StringNull()
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -202,14 +267,15 @@ func (r *repositoryRegistryResource) Delete(
 	req resource.DeleteRequest,
 	resp *resource.DeleteResponse,
 ) {
-	var data repositoryRegistryResourceModel
+	var data repositoryRegistryResourceModelV1
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if err := r.client.RegistryDelete(data.RepositoryID.ValueInt64(), data.Address.ValueString()); err != nil {
+	err := r.client.WithContext(ctx).RegistryDelete(data.RepositoryID.ValueInt64(), data.Address.ValueString())
+	if err != nil {
 		resp.Diagnostics.AddError("Couldn't delete registry", err.Error())
 		return
 	}
@@ -241,3 +307,64 @@ func (r *repositoryRegistryResource) ImportState(
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("repository_id"), repoID)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("address"), idParts[1])...)
 }
+
+func (r *repositoryRegistryResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// State upgrade implementation from 0 (prior state version) to 1 (Schema.Version)
+		0: {
+			// add password_wo and password_wo_version attributes
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.Int64Attribute{
+						Computed:    true,
+						Description: "the id of the registry",
+						PlanModifiers: []planmodifier.Int64{
+							int64planmodifier.UseStateForUnknown(),
+						},
+					},
+					"repository_id": schema.Int64Attribute{
+						Required:    true,
+						Description: "the ID of the repository",
+						PlanModifiers: []planmodifier.Int64{
+							int64planmodifier.RequiresReplace(),
+						},
+					},
+					"address": schema.StringAttribute{
+						Required:    true,
+						Description: "the address of the registry (e.g. docker.io)",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"username": schema.StringAttribute{
+						Required:    true,
+						Description: "username used for authentication",
+					},
+					"password": schema.StringAttribute{
+						Required:    true,
+						Description: "password used for authentication",
+						Sensitive:   true,
+					},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var priorStateData repositoryRegistryResourceModelV0
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &priorStateData)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, repositoryRegistryResourceModelV1{
+					ID:                priorStateData.ID,
+					RepositoryID:      priorStateData.RepositoryID,
+					Address:           priorStateData.Address,
+					Username:          priorStateData.Username,
+					Password:          priorStateData.Password,
+					PasswordWO:        types.StringNull(),
+					PasswordWOVersion: types.Int64Null(),
+				})...)
+			},
+		},
+	}
+}
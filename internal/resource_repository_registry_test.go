@@ -3,6 +3,7 @@ package internal_test
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"slices"
 	"strconv"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestRepositoryRegistryResource(t *testing.T) {
@@ -161,6 +163,155 @@ resource "woodpecker_repository_registry" "test_registry" {
 	})
 }
 
+// previousProviderVersion is the last release of the provider before
+// woodpecker_repository_registry moved to schema version 1.
+const previousProviderVersion = "0.5.0"
+
+func TestRepositoryRegistryResourceUpgradeState(t *testing.T) {
+	t.Parallel()
+
+	repo := activateRepo(t, createRepo(t))
+
+	address := fmt.Sprintf("%s.localhost", uuid.NewString())
+	cfg := fmt.Sprintf(`
+resource "woodpecker_repository_registry" "test_registry" {
+	repository_id = %d
+	address = "%s"
+	username = "test"
+	password = "test"
+}
+`, repo.ID, address)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		CheckDestroy: checkRepositoryRegistryResourceDestroy(map[int64][]string{
+			repo.ID: {address},
+		}),
+		Steps: []resource.TestStep{
+			{ // create registry with the previous provider version (schema version 0)
+				ExternalProviders: map[string]resource.ExternalProvider{
+					"woodpecker": {
+						Source:            "Kichiyaki/woodpecker",
+						VersionConstraint: previousProviderVersion,
+					},
+				},
+				Config: cfg,
+			},
+			{ // upgrade state with the current provider
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Config:                   cfg,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("woodpecker_repository_registry.test_registry", "address", address),
+					resource.TestCheckResourceAttr("woodpecker_repository_registry.test_registry", "password", "test"),
+					resource.TestCheckNoResourceAttr("woodpecker_repository_registry.test_registry", "password_wo_version"),
+				),
+			},
+		},
+	})
+}
+
+func TestRepositoryRegistryResourceWriteOnly(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		repo := activateRepo(t, createRepo(t))
+
+		address := fmt.Sprintf("%s.localhost", uuid.NewString())
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+				tfversion.SkipBelow(tfversion.Version1_11_0),
+			},
+			CheckDestroy: checkRepositoryRegistryResourceDestroy(map[int64][]string{
+				repo.ID: {address},
+			}),
+			Steps: []resource.TestStep{
+				{ // create registry with a write-only password
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository_registry" "test_registry" {
+	repository_id = %d
+	address = "%s"
+	username = "test"
+	password_wo = "test"
+	password_wo_version = 1
+}
+`, repo.ID, address),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_repository_registry.test_registry", "id"),
+						resource.TestCheckResourceAttr("woodpecker_repository_registry.test_registry", "address", address),
+						resource.TestCheckResourceAttr(
+							"woodpecker_repository_registry.test_registry",
+							"password_wo_version",
+							"1",
+						),
+						resource.TestCheckNoResourceAttr("woodpecker_repository_registry.test_registry", "password"),
+						resource.TestCheckNoResourceAttr("woodpecker_repository_registry.test_registry", "password_wo"),
+					),
+				},
+				{ // rotate the write-only password by bumping its version (in-place update)
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository_registry" "test_registry" {
+	repository_id = %d
+	address = "%s"
+	username = "test"
+	password_wo = "test2"
+	password_wo_version = 2
+}
+`, repo.ID, address),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction(
+								"woodpecker_repository_registry.test_registry",
+								plancheck.ResourceActionUpdate,
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr(
+							"woodpecker_repository_registry.test_registry",
+							"password_wo_version",
+							"2",
+						),
+						resource.TestCheckNoResourceAttr("woodpecker_repository_registry.test_registry", "password_wo"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: password and password_wo are mutually exclusive", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+resource "woodpecker_repository_registry" "test_registry" {
+	repository_id = 123
+	address = "docker.io"
+	username = "test"
+	password = "test"
+	password_wo = "test"
+}
+`,
+					ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+				},
+			},
+		})
+	})
+}
+
 func checkRepositoryRegistryResourceDestroy(m map[int64][]string) func(state *terraform.State) error {
 	return func(_ *terraform.State) error {
 		for repoID, addresses := range m {
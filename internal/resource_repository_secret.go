@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -186,7 +187,14 @@ func (r *repositorySecretResource) Create(
 		return
 	}
 
-	secret, err := r.client.SecretCreate(data.RepositoryID.ValueInt64(), wData)
+	secret, err := r.client.WithContext(ctx).SecretCreate(data.RepositoryID.ValueInt64(), wData)
+	if errors.Is(err, woodpecker.ErrConflict) {
+		resp.Diagnostics.AddError(
+			"Secret already exists",
+			fmt.Sprintf("A secret with the name %q already exists, import it to manage it with Terraform: %s", wData.Name, err),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't create secret", err.Error())
 		return
@@ -211,9 +219,14 @@ func (r *repositorySecretResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	secret, err := r.client.Secret(data.RepositoryID.ValueInt64(), data.Name.ValueString())
+	secret, err := r.client.WithContext(ctx).Secret(data.RepositoryID.ValueInt64(), data.Name.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get secret", err.Error())
+		return
 	}
 
 	resp.Diagnostics.Append(data.setValues(ctx, secret)...)
@@ -248,7 +261,7 @@ func (r *repositorySecretResource) Update(
 		return
 	}
 
-	secret, err := r.client.SecretUpdate(data.RepositoryID.ValueInt64(), wData)
+	secret, err := r.client.WithContext(ctx).SecretUpdate(data.RepositoryID.ValueInt64(), wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update secret", err.Error())
 		return
@@ -277,7 +290,7 @@ func (r *repositorySecretResource) Delete(
 		return
 	}
 
-	if err := r.client.SecretDelete(data.RepositoryID.ValueInt64(), data.Name.ValueString()); err != nil {
+	if err := r.client.WithContext(ctx).SecretDelete(data.RepositoryID.ValueInt64(), data.Name.ValueString()); err != nil {
 		resp.Diagnostics.AddError("Couldn't delete secret", err.Error())
 		return
 	}
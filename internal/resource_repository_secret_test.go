@@ -178,6 +178,82 @@ resource "woodpecker_repository_secret" "test_secret" {
 		})
 	})
 
+	t.Run("OK: recreate secret deleted outside of terraform", func(t *testing.T) {
+		t.Parallel()
+
+		repo := activateRepo(t, createRepo(t))
+
+		name := uuid.NewString()
+		cfg := fmt.Sprintf(`
+resource "woodpecker_repository_secret" "test_secret" {
+	repository_id = %d
+	name = "%s"
+	value = "test123"
+	events = ["%s"]
+}
+`, repo.ID, name, woodpecker.EventPush)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkRepositorySecretResourceDestroy(map[int64][]string{repo.ID: {name}}),
+			Steps: []resource.TestStep{
+				{
+					Config: cfg,
+				},
+				{
+					PreConfig: func() {
+						if err := woodpeckerClient.SecretDelete(repo.ID, name); err != nil {
+							t.Fatal(err)
+						}
+					},
+					Config: cfg,
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("woodpecker_repository_secret.test_secret", plancheck.ResourceActionCreate),
+						},
+					},
+					Check: resource.TestCheckResourceAttr("woodpecker_repository_secret.test_secret", "name", name),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: secret already exists", func(t *testing.T) {
+		t.Parallel()
+
+		repo := activateRepo(t, createRepo(t))
+
+		name := uuid.NewString()
+
+		if _, err := woodpeckerClient.SecretCreate(repo.ID, &woodpecker.Secret{
+			Name:   name,
+			Value:  "test123",
+			Events: []string{woodpecker.EventPush},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository_secret" "test_secret" {
+	repository_id = %d
+	name = "%s"
+	value = "test123"
+	events = ["%s"]
+}
+`, repo.ID, name, woodpecker.EventPush),
+					// Woodpecker may report a duplicate secret as an internal error rather than a conflict
+					ExpectError: regexp.MustCompile(`Secret already exists|Couldn't create secret`),
+				},
+			},
+		})
+	})
+
 	t.Run("ERR: incorrect event value", func(t *testing.T) {
 		t.Parallel()
 
@@ -3,16 +3,21 @@ package internal_test
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
 )
 
 func TestRepositoryResource(t *testing.T) {
@@ -21,11 +26,13 @@ func TestRepositoryResource(t *testing.T) {
 	t.Run("OK", func(t *testing.T) {
 		t.Parallel()
 
-		repo1, repo2 := createRepo(t), createRepo(t)
+		repo, repo2 := createRepo(t), createRepo(t)
+		newName := uuid.NewString()
+		movedFullName := repo.Owner.UserName + "/" + newName
 		resource.Test(t, resource.TestCase{
 			PreCheck:                 func() { testAccPreCheck(t) },
 			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-			CheckDestroy:             checkRepositoryResourceDestroy(repo1.FullName, repo2.FullName),
+			CheckDestroy:             checkRepositoryResourceDestroy(repo.FullName, movedFullName, repo2.FullName),
 			Steps: []resource.TestStep{
 				{ // create repo
 					Config: fmt.Sprintf(`
@@ -33,22 +40,22 @@ resource "woodpecker_repository" "test_repo" {
 	full_name = "%s"
 	visibility = "%s"
 }
-`, repo1.FullName, woodpecker.VisibilityModePublic),
+`, repo.FullName, woodpecker.VisibilityModePublic),
 					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "id"),
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "forge_id"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
 							"forge_remote_id",
-							strconv.FormatInt(repo1.ID, 10),
+							strconv.FormatInt(repo.ID, 10),
 						),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "forge_url", repo1.HTMLURL),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "clone_url", repo1.CloneURL),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo1.Owner.UserName),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo1.Name),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo1.FullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "forge_url", repo.HTMLURL),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "clone_url", repo.CloneURL),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo.Owner.UserName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo.Name),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo.FullName),
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "avatar_url"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "default_branch", repo1.DefaultBranch),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "default_branch", repo.DefaultBranch),
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "timeout"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
@@ -58,7 +65,7 @@ resource "woodpecker_repository" "test_repo" {
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
 							"is_private",
-							strconv.FormatBool(repo1.Private),
+							strconv.FormatBool(repo.Private),
 						),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.network", "false"),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.security", "false"),
@@ -98,22 +105,22 @@ resource "woodpecker_repository" "test_repo" {
 	allow_deployments = true
 	allow_pull_requests = false
 }
-`, repo1.FullName, woodpecker.VisibilityModePrivate),
+`, repo.FullName, woodpecker.VisibilityModePrivate),
 					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "id"),
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "forge_id"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
 							"forge_remote_id",
-							strconv.FormatInt(repo1.ID, 10),
+							strconv.FormatInt(repo.ID, 10),
 						),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "forge_url", repo1.HTMLURL),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "clone_url", repo1.CloneURL),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo1.Owner.UserName),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo1.Name),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo1.FullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "forge_url", repo.HTMLURL),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "clone_url", repo.CloneURL),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo.Owner.UserName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo.Name),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo.FullName),
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "avatar_url"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "default_branch", repo1.DefaultBranch),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "default_branch", repo.DefaultBranch),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "timeout", "30"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
@@ -123,7 +130,7 @@ resource "woodpecker_repository" "test_repo" {
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
 							"is_private",
-							strconv.FormatBool(repo1.Private),
+							strconv.FormatBool(repo.Private),
 						),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.network", "true"),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.security", "false"),
@@ -161,22 +168,22 @@ resource "woodpecker_repository" "test_repo" {
 	netrc_trusted_plugins = ["woodpeckerci/plugin-docker-buildx"]
 	approval_allowed_users = ["%s"]
 }
-`, repo1.FullName, woodpecker.EventTag, repo1.Owner.UserName),
+`, repo.FullName, woodpecker.EventTag, repo.Owner.UserName),
 					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "id"),
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "forge_id"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
 							"forge_remote_id",
-							strconv.FormatInt(repo1.ID, 10),
+							strconv.FormatInt(repo.ID, 10),
 						),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "forge_url", repo1.HTMLURL),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "clone_url", repo1.CloneURL),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo1.Owner.UserName),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo1.Name),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo1.FullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "forge_url", repo.HTMLURL),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "clone_url", repo.CloneURL),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo.Owner.UserName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo.Name),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo.FullName),
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "avatar_url"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "default_branch", repo1.DefaultBranch),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "default_branch", repo.DefaultBranch),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "timeout", "30"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
@@ -186,7 +193,7 @@ resource "woodpecker_repository" "test_repo" {
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
 							"is_private",
-							strconv.FormatBool(repo1.Private),
+							strconv.FormatBool(repo.Private),
 						),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.network", "true"),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.security", "false"),
@@ -213,22 +220,68 @@ resource "woodpecker_repository" "test_repo" {
 						resource.TestCheckTypeSetElemAttr(
 							"woodpecker_repository.test_repo",
 							"approval_allowed_users.*",
-							repo1.Owner.UserName,
+							repo.Owner.UserName,
 						),
 					),
 				},
 				{ // import
 					ResourceName:      "woodpecker_repository.test_repo",
 					ImportState:       true,
-					ImportStateId:     repo1.FullName,
+					ImportStateId:     repo.FullName,
 					ImportStateVerify: true,
 				},
+				{ // move repo after it has been renamed on the forge
+					PreConfig: func() {
+						renameRepo(t, repo, newName)
+					},
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+}
+`, movedFullName),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("woodpecker_repository.test_repo", plancheck.ResourceActionUpdate),
+							plancheck.ExpectUnknownValue("woodpecker_repository.test_repo", tfjsonpath.New("name")),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr(
+							"woodpecker_repository.test_repo",
+							"forge_remote_id",
+							strconv.FormatInt(repo.ID, 10),
+						),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo.Owner.UserName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", newName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", movedFullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "timeout", "30"),
+					),
+				},
+				{ // take ownership and repair
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	take_ownership = true
+	repair_trigger = "1"
+}
+`, movedFullName),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("woodpecker_repository.test_repo", plancheck.ResourceActionUpdate),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", movedFullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "take_ownership", "true"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "repair_trigger", "1"),
+					),
+				},
 				{ // replace repo
 					Config: fmt.Sprintf(`
 resource "woodpecker_repository" "test_repo" {
 	full_name = "%s"
 }
-				`, repo2.FullName),
+`, repo2.FullName),
 					ConfigPlanChecks: resource.ConfigPlanChecks{
 						PreApply: []plancheck.PlanCheck{
 							plancheck.ExpectResourceAction("woodpecker_repository.test_repo", plancheck.ResourceActionReplace),
@@ -236,7 +289,6 @@ resource "woodpecker_repository" "test_repo" {
 					},
 					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "id"),
-						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "forge_id"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
 							"forge_remote_id",
@@ -247,42 +299,457 @@ resource "woodpecker_repository" "test_repo" {
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "owner", repo2.Owner.UserName),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo2.Name),
 						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo2.FullName),
-						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "avatar_url"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "default_branch", repo2.DefaultBranch),
-						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "timeout"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "is_active", "true"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: update fails after the repository has been moved", func(t *testing.T) {
+		t.Parallel()
+
+		// the patch request can be made to fail only on a fake server
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+
+		forgeRepo := srv.AddForgeRepo(woodpeckertest.SelfLogin, uuid.NewString())
+		newName := uuid.NewString()
+		movedFullName := forgeRepo.Owner + "/" + newName
+
+		cfg := func(fullName string, timeout int) string {
+			return fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	token = "%s"
+}
+
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	timeout = %d
+}
+`, srv.URL, woodpeckertest.Token, fullName, timeout)
+		}
+
+		var repoID int64
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{ // create repo
+					Config: cfg(forgeRepo.FullName, 30),
+					Check: resource.TestCheckResourceAttrWith("woodpecker_repository.test_repo", "id", func(value string) error {
+						var err error
+						repoID, err = strconv.ParseInt(value, 10, 64)
+						return err
+					}),
+				},
+				{ // move repo, the patch that follows fails
+					PreConfig: func() {
+						srv.RenameForgeRepo(forgeRepo.FullName, newName)
+						srv.FailRequests(http.MethodPatch, fmt.Sprintf("/api/repos/%d", repoID), 1, http.StatusInternalServerError)
+					},
+					Config:      cfg(movedFullName, 60),
+					ExpectError: regexp.MustCompile(`Couldn't update repository`),
+				},
+				{ // the move is kept in state, only the patch is planned again
+					Config: cfg(movedFullName, 60),
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("woodpecker_repository.test_repo", plancheck.ResourceActionUpdate),
+							plancheck.ExpectKnownValue(
+								"woodpecker_repository.test_repo",
+								tfjsonpath.New("full_name"),
+								knownvalue.StringExact(movedFullName),
+							),
+						},
+					},
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", movedFullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "timeout", "60"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: full_name in a different case", func(t *testing.T) {
+		t.Parallel()
+
+		repo := createRepo(t)
+		fullName := strings.ToUpper(repo.FullName)
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkRepositoryResourceDestroy(repo.FullName),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+}
+`, fullName),
+					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
-							"visibility",
-							woodpecker.VisibilityModePublic.String(),
+							"forge_remote_id",
+							strconv.FormatInt(repo.ID, 10),
 						),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "name", repo.Name),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", fullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "is_active", "true"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: forge_remote_id", func(t *testing.T) {
+		t.Parallel()
+
+		repo := createRepo(t)
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkRepositoryResourceDestroy(repo.FullName),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	forge_remote_id = "%d"
+}
+`, repo.FullName, repo.ID),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "id"),
+						resource.TestCheckResourceAttrSet("woodpecker_repository.test_repo", "forge_id"),
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
-							"is_private",
-							strconv.FormatBool(repo2.Private),
+							"forge_remote_id",
+							strconv.FormatInt(repo.ID, 10),
 						),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.network", "false"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.security", "false"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "trusted.volumes", "false"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "full_name", repo.FullName),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "is_active", "true"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: forge_remote_id doesn't match full_name", func(t *testing.T) {
+		t.Parallel()
+
+		repo1, repo2 := createRepo(t), createRepo(t)
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkRepositoryResourceDestroy(repo1.FullName, repo2.FullName),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	forge_remote_id = "%d"
+}
+`, repo1.FullName, repo2.ID),
+					ExpectError: regexp.MustCompile(`Repository name mismatch`),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: forge_id requires forge_remote_id", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	forge_id = 1
+}
+`, uuid.NewString()),
+					ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: delete_mode", func(t *testing.T) {
+		t.Parallel()
+
+		repo := createRepo(t)
+		createPipelineConfig(t, repo)
+
+		secretName := uuid.NewString()
+		cronName := uuid.NewString()
+
+		var repoID int64
+		var pipeline *woodpecker.Pipeline
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy: func(_ *terraform.State) error {
+				if _, err := woodpeckerClient.Repo(repoID); !errors.Is(err, woodpecker.ErrNotFound) {
+					return fmt.Errorf("got error %v, want %w", err, woodpecker.ErrNotFound)
+				}
+				return nil
+			},
+			Steps: []resource.TestStep{
+				{ // create repo
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	timeout = 30
+}
+`, repo.FullName),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrWith("woodpecker_repository.test_repo", "id", func(value string) error {
+							var err error
+							repoID, err = strconv.ParseInt(value, 10, 64)
+							return err
+						}),
+					),
+				},
+				{ // destroy repo, by default it's only deactivated
+					PreConfig: func() {
+						if _, err := woodpeckerClient.SecretCreate(repoID, &woodpecker.Secret{
+							Name:   secretName,
+							Value:  "test123",
+							Events: []string{woodpecker.EventPush},
+						}); err != nil {
+							t.Fatal(err)
+						}
+						if _, err := woodpeckerClient.CronCreate(repoID, &woodpecker.Cron{
+							Name:     cronName,
+							Schedule: "@daily",
+							Branch:   repo.DefaultBranch,
+						}); err != nil {
+							t.Fatal(err)
+						}
+						pipeline = createPipeline(t, &woodpecker.Repo{ID: repoID}, repo.DefaultBranch)
+					},
+					Config: `
+data "woodpecker_user" "current" {
+	login = ""
+}
+`,
+					Check: func(_ *terraform.State) error {
+						deactivated, err := woodpeckerClient.Repo(repoID)
+						if err != nil {
+							return err
+						}
+						if deactivated.IsActive {
+							return errors.New("got active repo, want inactive")
+						}
+						return nil
+					},
+				},
+				{ // the deactivated repo is activated again
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	delete_mode = "remove"
+}
+`, repo.FullName),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttrWith("woodpecker_repository.test_repo", "id", func(value string) error {
+							if value != strconv.FormatInt(repoID, 10) {
+								return fmt.Errorf("got id %s, want %d", value, repoID)
+							}
+							return nil
+						}),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "is_active", "true"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "timeout", "30"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "delete_mode", "remove"),
+						func(_ *terraform.State) error {
+							// the secret, cron and pipeline survive the deactivation
+							if _, err := woodpeckerClient.Secret(repoID, secretName); err != nil {
+								return fmt.Errorf("couldn't get secret %s: %w", secretName, err)
+							}
+
+							crons, err := woodpeckerClient.CronList(repoID, woodpecker.CronListOptions{})
+							if err != nil {
+								return fmt.Errorf("couldn't list crons: %w", err)
+							}
+							if !slices.ContainsFunc(crons, func(cron *woodpecker.Cron) bool {
+								return cron.Name == cronName
+							}) {
+								return fmt.Errorf("cron %s not found", cronName)
+							}
+
+							if _, err = woodpeckerClient.Pipeline(repoID, pipeline.Number); err != nil {
+								return fmt.Errorf("couldn't get pipeline #%d: %w", pipeline.Number, err)
+							}
+
+							return nil
+						},
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: incorrect delete_mode value", func(t *testing.T) {
+		t.Parallel()
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	delete_mode = "archive"
+}
+`, uuid.NewString()),
+					ExpectError: regexp.MustCompile(`Attribute delete_mode value must be one of`),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: extensions and retention", func(t *testing.T) {
+		t.Parallel()
+
+		repo := createRepo(t)
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkRepositoryResourceDestroy(repo.FullName),
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	config_extension_endpoint = "https://config.example.com"
+	secret_extension_endpoint = "https://secrets.example.com"
+	max_pipeline_age = 30
+}
+`, repo.FullName),
+					Check: resource.ComposeAggregateTestCheckFunc(
 						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
-							"require_approval",
-							woodpecker.ApprovalModeForks.String(),
+							"config_extension_endpoint",
+							"https://config.example.com",
 						),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "is_active", "true"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "allow_pull_requests", "true"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "allow_deployments", "false"),
-						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "config_file", ""),
-						resource.TestCheckTypeSetElemAttr(
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "registry_extension_endpoint", ""),
+						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
-							"cancel_previous_pipeline_events.*",
-							woodpecker.EventPush,
+							"secret_extension_endpoint",
+							"https://secrets.example.com",
 						),
-						resource.TestCheckTypeSetElemAttr(
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "max_pipeline_age", "30"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "log_expiry", "0"),
+					),
+				},
+				{
+					Config: fmt.Sprintf(`
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	config_extension_endpoint = ""
+	registry_extension_endpoint = "https://registries.example.com"
+	secret_extension_endpoint = "https://secrets.example.com"
+	max_pipeline_age = 60
+	log_expiry = 7
+}
+`, repo.FullName),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "config_extension_endpoint", ""),
+						resource.TestCheckResourceAttr(
 							"woodpecker_repository.test_repo",
-							"cancel_previous_pipeline_events.*",
-							woodpecker.EventPull,
+							"registry_extension_endpoint",
+							"https://registries.example.com",
 						),
-						resource.TestCheckNoResourceAttr("woodpecker_repository.test_repo", "netrc_trusted_plugins"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "max_pipeline_age", "60"),
+						resource.TestCheckResourceAttr("woodpecker_repository.test_repo", "log_expiry", "7"),
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("ERR: extensions not supported by the server", func(t *testing.T) {
+		t.Parallel()
+
+		// the shared server can't be downgraded, a separate fake server is used instead
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		srv.SetVersion("3.7.0")
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	token = "%s"
+}
+
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	log_expiry = 7
+}
+`, srv.URL, woodpeckertest.Token, uuid.NewString()),
+					ExpectError: regexp.MustCompile(`log_expiry requires Woodpecker 3.8.0 or later`),
+				},
+			},
+		})
+	})
+
+	t.Run("OK: only settings newer than the server are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// the shared server can't be downgraded, a separate fake server is used instead
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		srv.SetVersion("3.7.0")
+
+		forgeRepo := srv.AddForgeRepo(woodpeckertest.SelfLogin, uuid.NewString())
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			Steps: []resource.TestStep{
+				{ // config_extension_endpoint is supported since Woodpecker 3.0.0
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	token = "%s"
+}
+
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	config_extension_endpoint = "https://config.example.com"
+}
+`, srv.URL, woodpeckertest.Token, forgeRepo.FullName),
+					Check: resource.TestCheckResourceAttr(
+						"woodpecker_repository.test_repo",
+						"config_extension_endpoint",
+						"https://config.example.com",
+					),
+				},
+				{ // secret_extension_endpoint requires Woodpecker 3.8.0
+					Config: fmt.Sprintf(`
+provider "woodpecker" {
+	server = "%s"
+	token = "%s"
+}
+
+resource "woodpecker_repository" "test_repo" {
+	full_name = "%s"
+	config_extension_endpoint = "https://config.example.com"
+	secret_extension_endpoint = "https://secrets.example.com"
+}
+`, srv.URL, woodpeckertest.Token, forgeRepo.FullName),
+					ExpectError: regexp.MustCompile(
+						`secret_extension_endpoint requires Woodpecker 3.8.0 or later, current version: 3.7.0`,
 					),
 				},
 			},
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
@@ -174,7 +175,14 @@ func (r *secretResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	secret, err := r.client.GlobalSecretCreate(wData)
+	secret, err := r.client.WithContext(ctx).GlobalSecretCreate(wData)
+	if errors.Is(err, woodpecker.ErrConflict) {
+		resp.Diagnostics.AddError(
+			"Secret already exists",
+			fmt.Sprintf("A secret with the name %q already exists, import it to manage it with Terraform: %s", wData.Name, err),
+		)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't create secret", err.Error())
 		return
@@ -199,9 +207,14 @@ func (r *secretResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	secret, err := r.client.GlobalSecret(data.Name.ValueString())
+	secret, err := r.client.WithContext(ctx).GlobalSecret(data.Name.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get secret", err.Error())
+		return
 	}
 
 	resp.Diagnostics.Append(data.setValues(ctx, secret)...)
@@ -232,7 +245,7 @@ func (r *secretResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	secret, err := r.client.GlobalSecretUpdate(wData)
+	secret, err := r.client.WithContext(ctx).GlobalSecretUpdate(wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update secret", err.Error())
 		return
@@ -257,7 +270,7 @@ func (r *secretResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	if err := r.client.GlobalSecretDelete(data.Name.ValueString()); err != nil {
+	if err := r.client.WithContext(ctx).GlobalSecretDelete(data.Name.ValueString()); err != nil {
 		resp.Diagnostics.AddError("Couldn't delete secret", err.Error())
 		return
 	}
@@ -148,6 +148,44 @@ resource "woodpecker_secret" "test_secret" {
 			},
 		})
 	})
+
+	t.Run("OK: recreate secret deleted outside of terraform", func(t *testing.T) {
+		t.Parallel()
+
+		name := uuid.NewString()
+		cfg := fmt.Sprintf(`
+resource "woodpecker_secret" "test_secret" {
+	name = "%s"
+	value = "test123"
+	events = ["%s"]
+}
+`, name, woodpecker.EventPush)
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:                 func() { testAccPreCheck(t) },
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			CheckDestroy:             checkSecretResourceDestroy(name),
+			Steps: []resource.TestStep{
+				{
+					Config: cfg,
+				},
+				{
+					PreConfig: func() {
+						if err := woodpeckerClient.GlobalSecretDelete(name); err != nil {
+							t.Fatal(err)
+						}
+					},
+					Config: cfg,
+					ConfigPlanChecks: resource.ConfigPlanChecks{
+						PreApply: []plancheck.PlanCheck{
+							plancheck.ExpectResourceAction("woodpecker_secret.test_secret", plancheck.ResourceActionCreate),
+						},
+					},
+					Check: resource.TestCheckResourceAttr("woodpecker_secret.test_secret", "name", name),
+				},
+			},
+		})
+	})
 }
 
 func TestSecretResourceWriteOnly(t *testing.T) {
@@ -235,7 +273,9 @@ resource "woodpecker_secret" "test_secret" {
 
 func checkSecretResourceDestroy(names ...string) func(state *terraform.State) error {
 	return func(_ *terraform.State) error {
-		secrets, err := woodpeckerClient.GlobalSecretList(woodpecker.SecretListOptions{})
+		secrets, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Secret, error) {
+			return woodpeckerClient.GlobalSecretList(woodpecker.SecretListOptions{ListOptions: opt})
+		})
 		if err != nil {
 			return fmt.Errorf("couldn't list secrets: %w", err)
 		}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
@@ -121,7 +122,7 @@ func (r *userResource) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	user, err := r.client.UserPost(wData)
+	user, err := r.client.WithContext(ctx).UserPost(wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't create user", err.Error())
 		return
@@ -143,9 +144,14 @@ func (r *userResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	user, err := r.client.User(data.Login.ValueString())
+	user, err := r.client.WithContext(ctx).User(data.Login.ValueString())
+	if errors.Is(err, woodpecker.ErrNotFound) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't get user", err.Error())
+		return
 	}
 
 	resp.Diagnostics.Append(data.setValues(ctx, user)...)
@@ -170,7 +176,7 @@ func (r *userResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
-	user, err := r.client.UserPatch(wData)
+	user, err := r.client.WithContext(ctx).UserPatch(wData)
 	if err != nil {
 		resp.Diagnostics.AddError("Couldn't update user", err.Error())
 		return
@@ -192,7 +198,7 @@ func (r *userResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	if err := r.client.UserDel(data.Login.ValueString()); err != nil {
+	if err := r.client.WithContext(ctx).UserDel(data.Login.ValueString()); err != nil {
 		resp.Diagnostics.AddError("Couldn't delete user", err.Error())
 		return
 	}
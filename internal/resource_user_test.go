@@ -105,7 +105,9 @@ resource "woodpecker_user" "test_user" {
 
 func checkUserResourceDestroy(logins ...string) func(state *terraform.State) error {
 	return func(_ *terraform.State) error {
-		users, err := woodpeckerClient.UserList(woodpecker.UserListOptions{})
+		users, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.User, error) {
+			return woodpeckerClient.UserList(woodpecker.UserListOptions{ListOptions: opt})
+		})
 		if err != nil {
 			return fmt.Errorf("couldn't list users: %w", err)
 		}
@@ -1,18 +1,25 @@
 package internal_test
 
 import (
+	"encoding/base64"
+	"fmt"
 	"strconv"
 	"sync"
 	"testing"
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
 	"github.com/google/uuid"
 )
 
 func createRepo(tb testing.TB) *gitea.Repository {
 	tb.Helper()
 
+	if fakeServer != nil {
+		return createFakeForgeRepo(woodpeckertest.SelfLogin)
+	}
+
 	repo, _, err := giteaClient.CreateRepo(gitea.CreateRepoOption{
 		Name:          uuid.NewString(),
 		Description:   uuid.NewString(),
@@ -36,6 +43,10 @@ func createRepo(tb testing.TB) *gitea.Repository {
 func createOrgRepo(tb testing.TB) *gitea.Repository {
 	tb.Helper()
 
+	if fakeServer != nil {
+		return createFakeForgeRepo(uuid.NewString())
+	}
+
 	org, _, err := giteaClient.CreateOrg(gitea.CreateOrgOption{
 		Name:                      uuid.NewString(),
 		FullName:                  uuid.NewString(),
@@ -86,6 +97,11 @@ func createOrg(tb testing.TB) *woodpecker.Org {
 func createBranch(tb testing.TB, repo *gitea.Repository) *gitea.Branch {
 	tb.Helper()
 
+	// the fake server doesn't track branches
+	if fakeServer != nil {
+		return &gitea.Branch{Name: uuid.NewString()}
+	}
+
 	branch, _, err := giteaClient.CreateBranch(repo.Owner.UserName, repo.Name, gitea.CreateBranchOption{
 		BranchName: uuid.NewString(),
 	})
@@ -96,6 +112,69 @@ func createBranch(tb testing.TB, repo *gitea.Repository) *gitea.Branch {
 	return branch
 }
 
+const pipelineConfig = `when:
+  - event: manual
+
+steps:
+  - name: build
+    image: alpine
+    commands:
+      - echo "hello"
+`
+
+// createPipelineConfig adds a pipeline config to the default branch of the given repo.
+func createPipelineConfig(tb testing.TB, repo *gitea.Repository) {
+	tb.Helper()
+
+	// the fake server doesn't read pipeline configs
+	if fakeServer != nil {
+		return
+	}
+
+	_, _, err := giteaClient.CreateFile(repo.Owner.UserName, repo.Name, ".woodpecker.yaml", gitea.CreateFileOptions{
+		Content: base64.StdEncoding.EncodeToString([]byte(pipelineConfig)),
+	})
+	if err != nil {
+		tb.Fatalf("got unexpected error while creating pipeline config: %s", err)
+	}
+}
+
+// renameRepo renames the given repo on the forge, Woodpecker keeps the old name until the repo is moved.
+func renameRepo(tb testing.TB, repo *gitea.Repository, newName string) {
+	tb.Helper()
+
+	if fakeServer != nil {
+		fakeServer.RenameForgeRepo(repo.FullName, newName)
+		return
+	}
+
+	_, _, err := giteaClient.EditRepo(repo.Owner.UserName, repo.Name, gitea.EditRepoOption{
+		Name: &newName,
+	})
+	if err != nil {
+		tb.Fatalf("got unexpected error while renaming repo: %s", err)
+	}
+	tb.Cleanup(func() {
+		_, _ = giteaClient.DeleteRepo(repo.Owner.UserName, newName)
+	})
+}
+
+// createFakeForgeRepo registers a repo in the forge of the fake server
+// and returns it in the same shape as the Gitea API would.
+func createFakeForgeRepo(owner string) *gitea.Repository {
+	repo := fakeServer.AddForgeRepo(owner, uuid.NewString())
+	id, _ := strconv.ParseInt(repo.ForgeRemoteID, 10, 64)
+	return &gitea.Repository{
+		ID:            id,
+		Owner:         &gitea.User{UserName: repo.Owner},
+		Name:          repo.Name,
+		FullName:      repo.FullName,
+		HTMLURL:       repo.ForgeURL,
+		CloneURL:      repo.Clone,
+		DefaultBranch: repo.Branch,
+	}
+}
+
 var activateRepoMu sync.Mutex
 
 func activateRepo(tb testing.TB, giteaRepo *gitea.Repository) *woodpecker.Repo {
@@ -113,8 +192,89 @@ func activateRepo(tb testing.TB, giteaRepo *gitea.Repository) *woodpecker.Repo {
 		tb.Fatalf("got unexpected error while activating repo: %s", err)
 	}
 	tb.Cleanup(func() {
-		_ = woodpeckerClient.RepoDel(repo.ID)
+		_ = woodpeckerClient.RepoDel(repo.ID, woodpecker.RepoDelOptions{})
 	})
 
 	return repo
 }
+
+func createPipeline(tb testing.TB, repo *woodpecker.Repo, branch string) *woodpecker.Pipeline {
+	tb.Helper()
+
+	pipeline, err := woodpeckerClient.PipelineCreate(repo.ID, &woodpecker.PipelineOptions{
+		Branch: branch,
+	})
+	if err != nil {
+		tb.Fatalf("got unexpected error while creating pipeline: %s", err)
+	}
+	tb.Cleanup(func() {
+		_ = woodpeckerClient.PipelineStop(repo.ID, pipeline.Number)
+	})
+
+	return pipeline
+}
+
+func allowDeployments(tb testing.TB, repo *woodpecker.Repo) {
+	tb.Helper()
+
+	allow := true
+	if _, err := woodpeckerClient.RepoPatch(repo.ID, &woodpecker.RepoPatch{AllowDeployments: &allow}); err != nil {
+		tb.Fatalf("got unexpected error while allowing deployments: %s", err)
+	}
+}
+
+// finishPipelines makes the fake server finish every new pipeline of the given repo with the given status,
+// the build step gets the given log entries.
+// The test environment doesn't start an agent, pipelines never run on a real server,
+// so the test is skipped unless it runs against the fake server.
+func finishPipelines(tb testing.TB, repo *woodpecker.Repo, status string, entries ...woodpecker.LogEntry) {
+	tb.Helper()
+
+	if fakeServer == nil {
+		tb.Skip("pipelines can run only against the fake server, the test environment doesn't start an agent")
+	}
+
+	fakeServer.FinishPipelines(repo.ID, status, entries...)
+}
+
+// logLines returns n stdout log entries with the content "line 1", "line 2", ...
+func logLines(n int) []woodpecker.LogEntry {
+	entries := make([]woodpecker.LogEntry, 0, n)
+	for i := range n {
+		entries = append(entries, woodpecker.LogEntry{
+			Data: fmt.Appendf(nil, "line %d", i+1),
+			Type: woodpecker.LogEntryStdout,
+		})
+	}
+	return entries
+}
+
+// runPipeline creates a pipeline, adds the given entries to the log of the build step
+// and finishes the pipeline with the given status.
+// The test environment doesn't start an agent, pipelines never run on a real server,
+// so the test is skipped unless it runs against the fake server.
+func runPipeline(
+	tb testing.TB,
+	repo *woodpecker.Repo,
+	branch string,
+	status string,
+	entries ...woodpecker.LogEntry,
+) *woodpecker.Pipeline {
+	tb.Helper()
+
+	if fakeServer == nil {
+		tb.Skip("pipelines can run only against the fake server, the test environment doesn't start an agent")
+	}
+
+	pipeline := createPipeline(tb, repo, branch)
+
+	fakeServer.AddStepLog(repo.ID, pipeline.Number, "build", entries...)
+	fakeServer.SetPipelineStatus(repo.ID, pipeline.Number, status)
+
+	p, err := woodpeckerClient.Pipeline(repo.ID, pipeline.Number)
+	if err != nil {
+		tb.Fatalf("got unexpected error while getting pipeline: %s", err)
+	}
+
+	return p
+}
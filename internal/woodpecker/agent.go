@@ -1,6 +1,9 @@
 package woodpecker
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+)
 
 const (
 	pathAgents     = "%s/api/agents"
@@ -16,10 +19,11 @@ func (c *client) AgentCreate(in *Agent) (*Agent, error) {
 }
 
 // AgentList returns a list of all registered agents.
-func (c *client) AgentList() ([]*Agent, error) {
+func (c *client) AgentList(opt AgentListOptions) ([]*Agent, error) {
 	out := make([]*Agent, 0, 5)
-	uri := fmt.Sprintf(pathAgents, c.addr)
-	return out, c.get(uri, &out)
+	uri, _ := url.Parse(fmt.Sprintf(pathAgents, c.addr))
+	uri.RawQuery = opt.getURLQuery().Encode()
+	return out, c.get(uri.String(), &out)
 }
 
 // Agent returns an agent by id.
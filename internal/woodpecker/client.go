@@ -16,7 +16,9 @@ package woodpecker
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -33,6 +35,13 @@ const (
 	// pathFeed           = "%s/api/user/feed"
 )
 
+var (
+	// ErrNotFound is matched by errors.Is for ClientError with the 404 Not Found status code.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is matched by errors.Is for ClientError with the 409 Conflict status code.
+	ErrConflict = errors.New("conflict")
+)
+
 type ClientError struct {
 	StatusCode int
 	Message    string
@@ -42,19 +51,51 @@ func (e *ClientError) Error() string {
 	return fmt.Sprintf("client error %d: %s", e.StatusCode, e.Message)
 }
 
+// Is reports whether the error matches one of the sentinel errors (ErrNotFound, ErrConflict).
+func (e *ClientError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	default:
+		return false
+	}
+}
+
 type client struct {
 	client *http.Client
 	addr   string
+	ctx    context.Context
+	retry  RetryPolicy
+	// err is returned by every request instead of sending it, see NewErrClient
+	err error
+	// forgeRepos is shared with the copies returned by WithContext, see RepoForgeLookup
+	forgeRepos *forgeRepoCache
 }
 
 // New returns a client at the specified url.
 func New(uri string) Client {
-	return &client{http.DefaultClient, strings.TrimSuffix(uri, "/")}
+	return &client{client: http.DefaultClient, addr: strings.TrimSuffix(uri, "/"), forgeRepos: new(forgeRepoCache)}
 }
 
 // NewClient returns a client at the specified url.
 func NewClient(uri string, cli *http.Client) Client {
-	return &client{cli, strings.TrimSuffix(uri, "/")}
+	return &client{client: cli, addr: strings.TrimSuffix(uri, "/"), forgeRepos: new(forgeRepoCache)}
+}
+
+// NewErrClient returns a client whose every request fails with err without being sent.
+// It can be used in place of a client that couldn't be configured.
+func NewErrClient(err error) Client {
+	return &client{client: http.DefaultClient, err: err, forgeRepos: new(forgeRepoCache)}
+}
+
+// WithContext returns a shallow copy of the client that uses the given context
+// for all requests it makes. Cancelling the context aborts in-flight requests.
+func (c *client) WithContext(ctx context.Context) Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
 }
 
 // SetClient sets the http.Client.
@@ -67,6 +108,11 @@ func (c *client) SetAddress(addr string) {
 	c.addr = addr
 }
 
+// SetRetryPolicy sets the policy used to retry failed requests.
+func (c *client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
 // LogLevel returns the current logging level.
 func (c *client) LogLevel() (*LogLevel, error) {
 	out := new(LogLevel)
@@ -122,38 +168,60 @@ func (c *client) do(rawURL, method string, in, out any) error {
 
 // Helper function to open an http request.
 func (c *client) open(rawURL, method string, in any) (io.ReadCloser, error) {
-	uri, err := url.Parse(rawURL)
-	if err != nil {
-		return nil, err
+	if c.err != nil {
+		return nil, c.err
 	}
-	req, err := http.NewRequest(method, uri.String(), nil)
+	uri, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, err
 	}
+	var body []byte
 	if in != nil {
-		decoded, decodeErr := json.Marshal(in)
-		if decodeErr != nil {
-			return nil, decodeErr
+		body, err = json.Marshal(in)
+		if err != nil {
+			return nil, err
 		}
-		buf := bytes.NewBuffer(decoded)
-		req.Body = io.NopCloser(buf)
-		req.ContentLength = int64(len(decoded))
-		req.Header.Set("Content-Length", strconv.Itoa(len(decoded)))
-		req.Header.Set("Content-Type", "application/json")
 	}
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	if resp.StatusCode > http.StatusPartialContent {
-		defer resp.Body.Close()
-		out, _ := io.ReadAll(resp.Body)
-		return nil, &ClientError{
-			StatusCode: resp.StatusCode,
-			Message:    string(out),
+	for retry := 0; ; retry++ {
+		req, err := http.NewRequestWithContext(ctx, method, uri.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			req.ContentLength = int64(len(body))
+			req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := c.client.Do(req)
+		if retry < c.retry.MaxRetries && c.retry.shouldRetry(ctx, method, resp, err) {
+			wait := c.retry.backoff(retry, resp)
+			if resp != nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode > http.StatusPartialContent {
+			defer resp.Body.Close()
+			out, _ := io.ReadAll(resp.Body)
+			return nil, &ClientError{
+				StatusCode: resp.StatusCode,
+				Message:    string(out),
+			}
 		}
+		return resp.Body, nil
 	}
-	return resp.Body, nil
 }
 
 // mapValues converts a map to `url.Values`.
@@ -0,0 +1,317 @@
+package woodpecker_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
+)
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK: self and version", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+
+		user, err := client.Self()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Login != woodpeckertest.SelfLogin || !user.Admin {
+			t.Errorf("got user %+v, want admin %s", user, woodpeckertest.SelfLogin)
+		}
+
+		ver, err := client.Version()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ver.Version != woodpeckertest.DefaultVersion {
+			t.Errorf("got version %s, want %s", ver.Version, woodpeckertest.DefaultVersion)
+		}
+	})
+
+	t.Run("OK: secret lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+
+		created, err := client.GlobalSecretCreate(&woodpecker.Secret{
+			Name:   "test",
+			Value:  "value",
+			Events: []string{woodpecker.EventPush},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.ID == 0 || created.Value != "" {
+			t.Errorf("got secret %+v, want non-zero id and redacted value", created)
+		}
+
+		updated, err := client.GlobalSecretUpdate(&woodpecker.Secret{
+			Name:   "test",
+			Events: []string{woodpecker.EventPush, woodpecker.EventTag},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(updated.Events, []string{woodpecker.EventPush, woodpecker.EventTag}) {
+			t.Errorf("got events %v, want [push tag]", updated.Events)
+		}
+
+		if err = client.GlobalSecretDelete("test"); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = client.GlobalSecret("test")
+		if !errors.Is(err, woodpecker.ErrNotFound) {
+			t.Errorf("got error %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("OK: repository activation", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+
+		forgeRepo := srv.AddForgeRepo("owner", "repo")
+
+		repo, err := client.RepoPost(woodpecker.RepoPostOptions{ForgeRemoteID: forgeRepo.ForgeRemoteID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repo.ID == 0 || !repo.IsActive {
+			t.Errorf("got repo %+v, want active repo with non-zero id", repo)
+		}
+
+		found, err := client.RepoLookup("owner/repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ID != repo.ID {
+			t.Errorf("got repo id %d, want %d", found.ID, repo.ID)
+		}
+
+		if _, err = client.OrgLookup("owner"); err != nil {
+			t.Errorf("couldn't find the repo owner org: %s", err)
+		}
+
+		if err = client.RepoDel(repo.ID, woodpecker.RepoDelOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		deactivated, err := client.Repo(repo.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deactivated.IsActive {
+			t.Error("got active repo, want inactive")
+		}
+	})
+
+	t.Run("OK: forge repository lookup", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+
+		forgeRepo := srv.AddForgeRepo("owner", "repo")
+
+		// the repository has never been activated, so it's only known to the forge
+		found, err := client.RepoForgeLookup("owner/repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ForgeRemoteID != forgeRepo.ForgeRemoteID {
+			t.Errorf("got forge remote id %s, want %s", found.ForgeRemoteID, forgeRepo.ForgeRemoteID)
+		}
+
+		repo, err := client.RepoPost(woodpecker.RepoPostOptions{
+			ForgeRemoteID: found.ForgeRemoteID,
+			ForgeID:       found.ForgeID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// once activated, a single lookup request is enough
+		requests := srv.Requests()
+		found, err = client.RepoForgeLookup("owner/repo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ID != repo.ID {
+			t.Errorf("got repo id %d, want %d", found.ID, repo.ID)
+		}
+		if n := srv.Requests() - requests; n != 1 {
+			t.Errorf("got %d requests, want 1", n)
+		}
+
+		if _, err = client.RepoForgeLookup("owner/unknown"); !errors.Is(err, woodpecker.ErrNotFound) {
+			t.Errorf("got error %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("OK: forge repository list is cached", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+
+		forgeRepo := srv.AddForgeRepo("owner", "repo")
+		otherForgeRepo := srv.AddForgeRepo("owner", "other")
+
+		if _, err := client.RepoForgeLookup(forgeRepo.FullName); err != nil {
+			t.Fatal(err)
+		}
+
+		// the forge list has been loaded by the first lookup, the copy made by WithContext shares it
+		requests := srv.Requests()
+		found, err := client.WithContext(context.Background()).RepoForgeLookup(otherForgeRepo.FullName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.ForgeRemoteID != otherForgeRepo.ForgeRemoteID {
+			t.Errorf("got forge remote id %s, want %s", found.ForgeRemoteID, otherForgeRepo.ForgeRemoteID)
+		}
+		if n := srv.Requests() - requests; n != 1 {
+			t.Errorf("got %d requests, want 1 (only the lookup)", n)
+		}
+	})
+
+	t.Run("OK: repository removal", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+
+		forgeRepo := srv.AddForgeRepo("owner", "repo")
+
+		repo, err := client.RepoPost(woodpecker.RepoPostOptions{ForgeRemoteID: forgeRepo.ForgeRemoteID})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = client.RepoDel(repo.ID, woodpecker.RepoDelOptions{Remove: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = client.Repo(repo.ID); !errors.Is(err, woodpecker.ErrNotFound) {
+			t.Errorf("got error %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("ERR: not found", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+
+		_, err := srv.Client().Repo(1234)
+		if !errors.Is(err, woodpecker.ErrNotFound) {
+			t.Errorf("got error %v, want ErrNotFound", err)
+		}
+		if errors.Is(err, woodpecker.ErrConflict) {
+			t.Error("error shouldn't match ErrConflict")
+		}
+
+		var clientErr *woodpecker.ClientError
+		if !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusNotFound {
+			t.Errorf("got error %v, want *ClientError with status code 404", err)
+		}
+	})
+
+	t.Run("ERR: conflict", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+
+		registry := &woodpecker.Registry{
+			Address:  "docker.io",
+			Username: "user",
+			Password: "password",
+		}
+		if _, err := client.GlobalRegistryCreate(registry); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := client.GlobalRegistryCreate(registry)
+		if !errors.Is(err, woodpecker.ErrConflict) {
+			t.Errorf("got error %v, want ErrConflict", err)
+		}
+		if errors.Is(err, woodpecker.ErrNotFound) {
+			t.Error("error shouldn't match ErrNotFound")
+		}
+	})
+
+	t.Run("ERR: unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+
+		_, err := woodpecker.New(srv.URL).Self()
+		var clientErr *woodpecker.ClientError
+		if !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("got error %v, want *ClientError with status code 401", err)
+		}
+	})
+
+	t.Run("ERR: canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := srv.Client().WithContext(ctx).Self()
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want context.Canceled", err)
+		}
+		if srv.Requests() != 0 {
+			t.Errorf("got %d requests, want 0", srv.Requests())
+		}
+	})
+	t.Run("ERR: error client", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+
+		errSetup := errors.New("setup failed")
+		client := woodpecker.NewErrClient(errSetup)
+		client.SetAddress(srv.URL)
+
+		_, err := client.WithContext(context.Background()).Self()
+		if !errors.Is(err, errSetup) {
+			t.Errorf("got error %v, want %v", err, errSetup)
+		}
+		for _, err := range client.StreamEvents() {
+			if !errors.Is(err, errSetup) {
+				t.Errorf("got error %v, want %v", err, errSetup)
+			}
+			break
+		}
+		if srv.Requests() != 0 {
+			t.Errorf("got %d requests, want 0", srv.Requests())
+		}
+	})
+}
@@ -14,6 +14,8 @@
 
 package woodpecker
 
+import "strconv"
+
 // Event values.
 const (
 	EventPush       = "push"
@@ -28,14 +30,15 @@ const (
 
 // Status values.
 const (
-	StatusBlocked = "blocked"
-	StatusSkipped = "skipped"
-	StatusPending = "pending"
-	StatusRunning = "running"
-	StatusSuccess = "success"
-	StatusFailure = "failure"
-	StatusKilled  = "killed"
-	StatusError   = "error"
+	StatusBlocked  = "blocked"
+	StatusSkipped  = "skipped"
+	StatusPending  = "pending"
+	StatusRunning  = "running"
+	StatusSuccess  = "success"
+	StatusFailure  = "failure"
+	StatusKilled   = "killed"
+	StatusError    = "error"
+	StatusDeclined = "declined"
 )
 
 // LogEntryType identifies the type of line in the logs.
@@ -49,6 +52,24 @@ const (
 	LogEntryProgress
 )
 
+// String returns the name of the log entry type, e.g. stdout or exit_code.
+func (t LogEntryType) String() string {
+	switch t {
+	case LogEntryStdout:
+		return "stdout"
+	case LogEntryStderr:
+		return "stderr"
+	case LogEntryExitCode:
+		return "exit_code"
+	case LogEntryMetadata:
+		return "metadata"
+	case LogEntryProgress:
+		return "progress"
+	default:
+		return strconv.Itoa(int(t))
+	}
+}
+
 // StepType identifies the type of step.
 type StepType string
 
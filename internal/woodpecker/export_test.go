@@ -0,0 +1,16 @@
+package woodpecker
+
+import (
+	"net/http"
+	"time"
+)
+
+// ParseRetryAfter exports parseRetryAfter for tests.
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	return parseRetryAfter(value, now)
+}
+
+// Backoff exports RetryPolicy.backoff for tests.
+func (p RetryPolicy) Backoff(retry int, resp *http.Response) time.Duration {
+	return p.backoff(retry, resp)
+}
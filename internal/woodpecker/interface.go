@@ -15,17 +15,25 @@
 package woodpecker
 
 import (
+	"context"
+	"iter"
 	"net/http"
 )
 
 // Client is used to communicate with a Woodpecker server.
 type Client interface {
+	// WithContext returns a copy of the client that uses the given context for all requests.
+	WithContext(ctx context.Context) Client
+
 	// SetClient sets the http.Client.
 	SetClient(*http.Client)
 
 	// SetAddress sets the server address.
 	SetAddress(string)
 
+	// SetRetryPolicy sets the policy used to retry failed requests.
+	SetRetryPolicy(RetryPolicy)
+
 	// Self returns the currently authenticated user.
 	Self() (*User, error)
 
@@ -50,6 +58,12 @@ type Client interface {
 	// RepoLookup returns a repository id by the owner and name.
 	RepoLookup(repoFullName string) (*Repo, error)
 
+	// RepoForgeLookup returns a repository by the owner and name, including repositories that have never been activated.
+	RepoForgeLookup(repoFullName string) (*Repo, error)
+
+	// RepoPermissions returns the permissions of the current user in a repository.
+	RepoPermissions(repoID int64) (*Perm, error)
+
 	// RepoList returns a list of all repositories to which the user has explicit
 	// access in the host system.
 	RepoList(opt RepoListOptions) ([]*Repo, error)
@@ -69,8 +83,8 @@ type Client interface {
 	// RepoRepair repairs the repository hooks.
 	RepoRepair(repoID int64) error
 
-	// RepoDel deletes a repository.
-	RepoDel(repoID int64) error
+	// RepoDel deactivates a repository, or removes it with all its data if opt.Remove is set.
+	RepoDel(repoID int64, opt RepoDelOptions) error
 
 	// Pipeline returns a repository pipeline by number.
 	Pipeline(repoID, pipeline int64) (*Pipeline, error)
@@ -108,6 +122,13 @@ type Client interface {
 	// StepLogEntries returns the LogEntries for the given pipeline step
 	StepLogEntries(repoID, pipeline, stepID int64) ([]*LogEntry, error)
 
+	// StreamLogs returns an iterator over the LogEntries of the given pipeline step,
+	// following the step live until it finishes.
+	StreamLogs(repoID, pipeline, stepID int64) iter.Seq2[*LogEntry, error]
+
+	// StreamEvents returns an iterator over the pipeline updates pushed by the server.
+	StreamEvents() iter.Seq2[*Event, error]
+
 	// Deploy triggers a deployment for an existing pipeline using the specified
 	// target environment.
 	Deploy(repoID, pipeline int64, opt DeployOptions) (*Pipeline, error)
@@ -187,6 +208,9 @@ type Client interface {
 	// OrgLookup returns an organization id by name.
 	OrgLookup(orgName string) (*Org, error)
 
+	// OrgPermissions returns the permissions of the current user in an organization.
+	OrgPermissions(orgID int64) (*OrgPerm, error)
+
 	// OrgSecret returns an organization secret by name.
 	OrgSecret(orgID int64, secret string) (*Secret, error)
 
@@ -202,6 +226,18 @@ type Client interface {
 	// OrgSecretDelete deletes an organization secret.
 	OrgSecretDelete(orgID int64, secret string) error
 
+	// OrgAgentList returns a list of all agents registered in an organization.
+	OrgAgentList(orgID int64, opt AgentListOptions) ([]*Agent, error)
+
+	// OrgAgentCreate creates an organization agent.
+	OrgAgentCreate(orgID int64, agent *Agent) (*Agent, error)
+
+	// OrgAgentUpdate updates an organization agent.
+	OrgAgentUpdate(orgID int64, agent *Agent) (*Agent, error)
+
+	// OrgAgentDelete deletes an organization agent.
+	OrgAgentDelete(orgID, agentID int64) error
+
 	// GlobalSecret returns an global secret by name.
 	GlobalSecret(secret string) (*Secret, error)
 
@@ -242,7 +278,7 @@ type Client interface {
 	CronUpdate(repoID int64, cron *Cron) (*Cron, error)
 
 	// AgentList returns a list of all registered agents.
-	AgentList() ([]*Agent, error)
+	AgentList(opt AgentListOptions) ([]*Agent, error)
 
 	// Agent returns an agent by id.
 	Agent(int64) (*Agent, error)
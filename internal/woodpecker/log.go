@@ -0,0 +1,43 @@
+package woodpecker
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// LogDecodeOptions controls how DecodeLogEntries rebuilds the log.
+type LogDecodeOptions struct {
+	Types []LogEntryType // only entries of the given types are kept, all types are kept if empty
+	Tail  int            // only the last Tail lines are kept, all lines are kept if <= 0
+}
+
+// DecodeLogEntries rebuilds the text of a step log from its entries.
+// Entries are ordered by line number (and by id for entries with the same line),
+// filtered by type and joined with a newline.
+func DecodeLogEntries(entries []*LogEntry, opt LogDecodeOptions) string {
+	lines := make([]*LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry == nil || (len(opt.Types) > 0 && !slices.Contains(opt.Types, entry.Type)) {
+			continue
+		}
+		lines = append(lines, entry)
+	}
+
+	slices.SortStableFunc(lines, func(a, b *LogEntry) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.ID, b.ID))
+	})
+
+	if opt.Tail > 0 && len(lines) > opt.Tail {
+		lines = lines[len(lines)-opt.Tail:]
+	}
+
+	var sb strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.Write(line.Data)
+	}
+	return sb.String()
+}
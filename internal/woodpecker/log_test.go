@@ -0,0 +1,68 @@
+package woodpecker_test
+
+import (
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+func TestDecodeLogEntries(t *testing.T) {
+	t.Parallel()
+
+	// entries are deliberately out of order
+	entries := []*woodpecker.LogEntry{
+		{ID: 4, Line: 3, Data: []byte("error: boom"), Type: woodpecker.LogEntryStderr},
+		{ID: 1, Line: 0, Data: []byte("+ make build"), Type: woodpecker.LogEntryStdout},
+		{ID: 5, Line: 4, Data: []byte("1"), Type: woodpecker.LogEntryExitCode},
+		{ID: 2, Line: 1, Data: []byte("building..."), Type: woodpecker.LogEntryStdout},
+		{ID: 3, Line: 2, Data: []byte("50%"), Type: woodpecker.LogEntryProgress},
+	}
+
+	tests := []struct {
+		name string
+		opt  woodpecker.LogDecodeOptions
+		want string
+	}{
+		{
+			name: "OK: all entries",
+			want: "+ make build\nbuilding...\n50%\nerror: boom\n1",
+		},
+		{
+			name: "OK: stdout and stderr",
+			opt: woodpecker.LogDecodeOptions{
+				Types: []woodpecker.LogEntryType{woodpecker.LogEntryStdout, woodpecker.LogEntryStderr},
+			},
+			want: "+ make build\nbuilding...\nerror: boom",
+		},
+		{
+			name: "OK: tail",
+			opt: woodpecker.LogDecodeOptions{
+				Types: []woodpecker.LogEntryType{woodpecker.LogEntryStdout, woodpecker.LogEntryStderr},
+				Tail:  2,
+			},
+			want: "building...\nerror: boom",
+		},
+		{
+			name: "OK: tail greater than the number of lines",
+			opt:  woodpecker.LogDecodeOptions{Tail: 100},
+			want: "+ make build\nbuilding...\n50%\nerror: boom\n1",
+		},
+		{
+			name: "OK: no matching entries",
+			opt: woodpecker.LogDecodeOptions{
+				Types: []woodpecker.LogEntryType{woodpecker.LogEntryMetadata},
+			},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := woodpecker.DecodeLogEntries(entries, tt.opt); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,10 +9,13 @@ const (
 	pathOrgs          = "%s/api/orgs"
 	pathOrg           = "%s/api/orgs/%d"
 	pathOrgLookup     = "%s/api/orgs/lookup/%s"
+	pathOrgPerms      = "%s/api/orgs/%d/permissions"
 	pathOrgSecrets    = "%s/api/orgs/%d/secrets"
 	pathOrgSecret     = "%s/api/orgs/%d/secrets/%s"
 	pathOrgRegistries = "%s/api/orgs/%d/registries"
 	pathOrgRegistry   = "%s/api/orgs/%d/registries/%s"
+	pathOrgAgents     = "%s/api/orgs/%d/agents"
+	pathOrgAgent      = "%s/api/orgs/%d/agents/%d"
 )
 
 // Org returns an organization by id.
@@ -40,6 +43,14 @@ func (c *client) OrgLookup(name string) (*Org, error) {
 	return out, err
 }
 
+// OrgPermissions returns the permissions of the current user in an organization.
+func (c *client) OrgPermissions(orgID int64) (*OrgPerm, error) {
+	out := new(OrgPerm)
+	uri := fmt.Sprintf(pathOrgPerms, c.addr, orgID)
+	err := c.get(uri, out)
+	return out, err
+}
+
 // OrgSecret returns an organization secret by name.
 func (c *client) OrgSecret(orgID int64, secret string) (*Secret, error) {
 	out := new(Secret)
@@ -117,3 +128,34 @@ func (c *client) OrgRegistryDelete(orgID int64, registry string) error {
 	uri := fmt.Sprintf(pathOrgRegistry, c.addr, orgID, registry)
 	return c.delete(uri)
 }
+
+// OrgAgentList returns a list of all agents registered in an organization.
+func (c *client) OrgAgentList(orgID int64, opt AgentListOptions) ([]*Agent, error) {
+	var out []*Agent
+	uri, _ := url.Parse(fmt.Sprintf(pathOrgAgents, c.addr, orgID))
+	uri.RawQuery = opt.getURLQuery().Encode()
+	err := c.get(uri.String(), &out)
+	return out, err
+}
+
+// OrgAgentCreate creates an organization agent.
+func (c *client) OrgAgentCreate(orgID int64, in *Agent) (*Agent, error) {
+	out := new(Agent)
+	uri := fmt.Sprintf(pathOrgAgents, c.addr, orgID)
+	err := c.post(uri, in, out)
+	return out, err
+}
+
+// OrgAgentUpdate updates an organization agent.
+func (c *client) OrgAgentUpdate(orgID int64, in *Agent) (*Agent, error) {
+	out := new(Agent)
+	uri := fmt.Sprintf(pathOrgAgent, c.addr, orgID, in.ID)
+	err := c.patch(uri, in, out)
+	return out, err
+}
+
+// OrgAgentDelete deletes an organization agent.
+func (c *client) OrgAgentDelete(orgID, agentID int64) error {
+	uri := fmt.Sprintf(pathOrgAgent, c.addr, orgID, agentID)
+	return c.delete(uri)
+}
@@ -0,0 +1,54 @@
+package woodpecker
+
+import "iter"
+
+// PaginatePerPage is the page size requested by Paginate.
+// It matches the server's default page size.
+const PaginatePerPage = 50
+
+// Paginate returns an iterator over the items of all pages returned by list.
+// Pages are fetched lazily, one after another, until the server returns a page
+// that is shorter than PaginatePerPage. If list returns an error,
+// the iterator yields it once and stops.
+//
+// Example:
+//
+//	for secret, err := range woodpecker.Paginate(func(opt woodpecker.ListOptions) ([]*woodpecker.Secret, error) {
+//		return client.GlobalSecretList(woodpecker.SecretListOptions{ListOptions: opt})
+//	}) {
+//		...
+//	}
+func Paginate[T any](list func(opt ListOptions) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page := 1; ; page++ {
+			items, err := list(ListOptions{Page: page, PerPage: PaginatePerPage})
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(items) < PaginatePerPage {
+				return
+			}
+		}
+	}
+}
+
+// CollectAll fetches all pages returned by list and returns their items.
+func CollectAll[T any](list func(opt ListOptions) ([]T, error)) ([]T, error) {
+	var out []T
+	for item, err := range Paginate(list) {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
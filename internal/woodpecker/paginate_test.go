@@ -0,0 +1,93 @@
+package woodpecker_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
+)
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	srv := woodpeckertest.NewServer()
+	t.Cleanup(srv.Close)
+	client := srv.Client()
+
+	const numSecrets = woodpecker.PaginatePerPage*2 + 3
+	for i := range numSecrets {
+		if _, err := client.GlobalSecretCreate(&woodpecker.Secret{
+			Name:   fmt.Sprintf("secret_%d", i),
+			Value:  "value",
+			Events: []string{woodpecker.EventPush},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listSecrets := func(opt woodpecker.ListOptions) ([]*woodpecker.Secret, error) {
+		return client.GlobalSecretList(woodpecker.SecretListOptions{ListOptions: opt})
+	}
+
+	t.Run("OK: collect all", func(t *testing.T) {
+		t.Parallel()
+
+		secrets, err := woodpecker.CollectAll(listSecrets)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secrets) != numSecrets {
+			t.Fatalf("got %d secrets, want %d", len(secrets), numSecrets)
+		}
+		for i, secret := range secrets {
+			if want := fmt.Sprintf("secret_%d", i); secret.Name != want {
+				t.Errorf("secrets[%d]: got %s, want %s", i, secret.Name, want)
+			}
+		}
+	})
+
+	t.Run("OK: stop early", func(t *testing.T) {
+		t.Parallel()
+
+		var pages []int
+		n := 0
+		for _, err := range woodpecker.Paginate(func(opt woodpecker.ListOptions) ([]*woodpecker.Secret, error) {
+			pages = append(pages, opt.Page)
+			return listSecrets(opt)
+		}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			n++
+			if n == woodpecker.PaginatePerPage+1 {
+				break
+			}
+		}
+
+		if len(pages) != 2 {
+			t.Errorf("got %d fetched pages, want 2", len(pages))
+		}
+	})
+
+	t.Run("ERR: list error", func(t *testing.T) {
+		t.Parallel()
+
+		errList := errors.New("list failed")
+		calls := 0
+		_, err := woodpecker.CollectAll(func(opt woodpecker.ListOptions) ([]*woodpecker.Secret, error) {
+			calls++
+			if opt.Page == 2 {
+				return nil, errList
+			}
+			return listSecrets(opt)
+		})
+		if !errors.Is(err, errList) {
+			t.Errorf("got error %v, want %v", err, errList)
+		}
+		if calls != 2 {
+			t.Errorf("got %d calls, want 2", calls)
+		}
+	})
+}
@@ -1,9 +1,13 @@
 package woodpecker
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,6 +18,7 @@ const (
 	pathRepoMove       = "%s/api/repos/%d/move"
 	pathChown          = "%s/api/repos/%d/chown"
 	pathRepair         = "%s/api/repos/%d/repair"
+	pathRepoPerms      = "%s/api/repos/%d/permissions"
 	pathPipelines      = "%s/api/repos/%d/pipelines"
 	pathPipeline       = "%s/api/repos/%d/pipelines/%v"
 	pathPipelineLogs   = "%s/api/repos/%d/logs/%d"
@@ -55,6 +60,10 @@ type OrgListOptions struct {
 	ListOptions
 }
 
+type AgentListOptions struct {
+	ListOptions
+}
+
 type DeployOptions struct {
 	DeployTo string            // override the target deploy value
 	Params   map[string]string // custom KEY=value parameters to be injected into the step environment
@@ -70,12 +79,20 @@ type PipelineLastOptions struct {
 
 type RepoPostOptions struct {
 	ForgeRemoteID string
+	// ForgeID is the forge the repository belongs to, the forge of the current user is used if it's zero.
+	ForgeID int64
 }
 
 type RepoMoveOptions struct {
 	To string
 }
 
+type RepoDelOptions struct {
+	// Remove deletes the repository with all its pipelines, secrets and other data.
+	// Otherwise, the repository is only deactivated and can be activated again later.
+	Remove bool
+}
+
 // QueryEncode returns the URL query parameters for the PipelineListOptions.
 func (opt *PipelineListOptions) QueryEncode() string {
 	query := opt.getURLQuery()
@@ -129,6 +146,9 @@ func (opt *PipelineLastOptions) QueryEncode() string {
 func (opt *RepoPostOptions) QueryEncode() string {
 	query := make(url.Values)
 	query.Add("forge_remote_id", opt.ForgeRemoteID)
+	if opt.ForgeID != 0 {
+		query.Add("forge_id", strconv.FormatInt(opt.ForgeID, 10))
+	}
 	return query.Encode()
 }
 
@@ -139,6 +159,14 @@ func (opt *RepoMoveOptions) QueryEncode() string {
 	return query.Encode()
 }
 
+// QueryEncode returns the URL query parameters for the RepoDelOptions.
+func (opt *RepoDelOptions) QueryEncode() string {
+	query := make(url.Values)
+	// Woodpecker removes the repository unless remove is explicitly set to false
+	query.Add("remove", strconv.FormatBool(opt.Remove))
+	return query.Encode()
+}
+
 // Repo returns a repository by id.
 func (c *client) Repo(repoID int64) (*Repo, error) {
 	out := new(Repo)
@@ -155,6 +183,69 @@ func (c *client) RepoLookup(fullName string) (*Repo, error) {
 	return out, err
 }
 
+// RepoForgeLookup returns a repository by name, including repositories that have never been activated.
+//
+// Woodpecker only stores repositories that have been activated at least once, so if the lookup fails,
+// the repository is searched in the list of all repositories the current user has access to on the forge.
+// Loading that list may require a full forge sync, so it's loaded once and cached for the lifetime of the client.
+// Only the forge attributes (e.g. ForgeRemoteID) of repositories found in the cached list are reliable.
+func (c *client) RepoForgeLookup(fullName string) (*Repo, error) {
+	repo, err := c.RepoLookup(fullName)
+	if !errors.Is(err, ErrNotFound) {
+		return repo, err
+	}
+
+	repos, err := c.forgeRepoList()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, repo := range repos {
+		if strings.EqualFold(repo.FullName, fullName) {
+			cp := *repo
+			return &cp, nil
+		}
+	}
+
+	return nil, &ClientError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("repository %s not found", fullName),
+	}
+}
+
+// forgeRepoCache holds the list of all repositories the current user has access to on the forge.
+type forgeRepoCache struct {
+	mu     sync.Mutex
+	loaded bool
+	repos  []*Repo
+}
+
+// forgeRepoList returns the cached list of all repositories the current user has access to on the forge,
+// loading it on the first call. Concurrent callers wait for the list to be loaded only once.
+func (c *client) forgeRepoList() ([]*Repo, error) {
+	c.forgeRepos.mu.Lock()
+	defer c.forgeRepos.mu.Unlock()
+
+	if !c.forgeRepos.loaded {
+		repos, err := c.RepoList(RepoListOptions{All: true})
+		if err != nil {
+			return nil, err
+		}
+		c.forgeRepos.repos = repos
+		c.forgeRepos.loaded = true
+	}
+
+	return c.forgeRepos.repos, nil
+}
+
+// RepoPermissions returns the permissions of the current user in a repository.
+func (c *client) RepoPermissions(repoID int64) (*Perm, error) {
+	out := new(Perm)
+	uri := fmt.Sprintf(pathRepoPerms, c.addr, repoID)
+	err := c.get(uri, out)
+	return out, err
+}
+
 // RepoPost activates a repository.
 func (c *client) RepoPost(opt RepoPostOptions) (*Repo, error) {
 	out := new(Repo)
@@ -186,11 +277,11 @@ func (c *client) RepoPatch(repoID int64, in *RepoPatch) (*Repo, error) {
 	return out, err
 }
 
-// RepoDel deletes a repository.
-func (c *client) RepoDel(repoID int64) error {
-	uri := fmt.Sprintf(pathRepo, c.addr, repoID)
-	err := c.delete(uri)
-	return err
+// RepoDel deactivates or removes a repository.
+func (c *client) RepoDel(repoID int64, opt RepoDelOptions) error {
+	uri, _ := url.Parse(fmt.Sprintf(pathRepo, c.addr, repoID))
+	uri.RawQuery = opt.QueryEncode()
+	return c.delete(uri.String())
 }
 
 // RepoMove moves a repository.
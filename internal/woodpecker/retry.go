@@ -0,0 +1,123 @@
+package woodpecker
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried.
+//
+// Requests that use an idempotent method (GET, HEAD, OPTIONS, PUT, DELETE) are retried
+// on network errors and on 429 Too Many Requests, 502 Bad Gateway and 503 Service Unavailable responses.
+// Other requests (POST, PATCH) are only retried on 429 Too Many Requests
+// as the server rejects them before they're processed.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries, 0 disables retries.
+	MaxRetries int
+	// WaitMin is the time to wait before the first retry.
+	// The wait time doubles with every following retry.
+	WaitMin time.Duration
+	// WaitMax is the upper limit of the wait time between retries,
+	// it also caps the Retry-After returned by the server.
+	WaitMax time.Duration
+}
+
+func (p RetryPolicy) shouldRetry(ctx context.Context, method string, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded) &&
+			isIdempotentMethod(method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return isIdempotentMethod(method)
+	default:
+		return false
+	}
+}
+
+// backoff returns how long to wait before the given retry (counting from 0).
+// It honors the Retry-After header (up to WaitMax) if the server sent one,
+// otherwise it uses an exponential backoff with jitter.
+func (p RetryPolicy) backoff(retry int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if p.WaitMax > 0 {
+				wait = min(wait, p.WaitMax)
+			}
+			return wait
+		}
+	}
+
+	wait := p.WaitMin
+	for range retry {
+		wait *= 2
+		if p.WaitMax > 0 && wait >= p.WaitMax {
+			break
+		}
+	}
+	if p.WaitMax > 0 && wait > p.WaitMax {
+		wait = p.WaitMax
+	}
+
+	// equal jitter: wait at least half of the computed time
+	if half := wait / 2; half > 0 {
+		wait = half + rand.N(half+1)
+	}
+
+	return wait
+}
+
+// parseRetryAfter parses the value of the Retry-After header,
+// which is either a number of seconds or an HTTP date relative to now.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,307 @@
+package woodpecker_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := woodpecker.RetryPolicy{
+		MaxRetries: 3,
+		WaitMin:    time.Millisecond,
+		WaitMax:    10 * time.Millisecond,
+	}
+
+	t.Run("OK: GET retried on 503", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+		client.SetRetryPolicy(policy)
+
+		srv.FailNext(policy.MaxRetries, http.StatusServiceUnavailable)
+
+		if _, err := client.Self(); err != nil {
+			t.Fatal(err)
+		}
+		if want := policy.MaxRetries + 1; srv.Requests() != want {
+			t.Errorf("got %d requests, want %d", srv.Requests(), want)
+		}
+	})
+
+	t.Run("OK: POST retried on 429", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+		client.SetRetryPolicy(policy)
+
+		srv.FailNext(1, http.StatusTooManyRequests)
+
+		_, err := client.GlobalSecretCreate(&woodpecker.Secret{
+			Name:   "test",
+			Value:  "value",
+			Events: []string{woodpecker.EventPush},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if srv.Requests() != 2 {
+			t.Errorf("got %d requests, want 2", srv.Requests())
+		}
+	})
+
+	t.Run("ERR: POST not retried on 503", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+		client.SetRetryPolicy(policy)
+
+		srv.FailNext(1, http.StatusServiceUnavailable)
+
+		_, err := client.GlobalSecretCreate(&woodpecker.Secret{
+			Name:   "test",
+			Value:  "value",
+			Events: []string{woodpecker.EventPush},
+		})
+		var clientErr *woodpecker.ClientError
+		if !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("got error %v, want *ClientError with status code 503", err)
+		}
+		if srv.Requests() != 1 {
+			t.Errorf("got %d requests, want 1", srv.Requests())
+		}
+	})
+
+	t.Run("ERR: retries exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+		client := srv.Client()
+		client.SetRetryPolicy(policy)
+
+		srv.FailNext(policy.MaxRetries+1, http.StatusBadGateway)
+
+		_, err := client.Self()
+		var clientErr *woodpecker.ClientError
+		if !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusBadGateway {
+			t.Errorf("got error %v, want *ClientError with status code 502", err)
+		}
+		if want := policy.MaxRetries + 1; srv.Requests() != want {
+			t.Errorf("got %d requests, want %d", srv.Requests(), want)
+		}
+	})
+
+	t.Run("OK: Retry-After capped at WaitMax", func(t *testing.T) {
+		t.Parallel()
+
+		var requests atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":1,"login":"test"}`))
+		}))
+		defer srv.Close()
+
+		client := woodpecker.NewClient(srv.URL, srv.Client())
+		client.SetRetryPolicy(policy)
+
+		start := time.Now()
+		if _, err := client.Self(); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("got request duration %s, want the wait capped at %s", elapsed, policy.WaitMax)
+		}
+		if requests.Load() != 2 {
+			t.Errorf("got %d requests, want 2", requests.Load())
+		}
+	})
+
+	t.Run("ERR: retries disabled", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		defer srv.Close()
+
+		srv.FailNext(1, http.StatusServiceUnavailable)
+
+		if _, err := srv.Client().Self(); err == nil {
+			t.Error("got nil error, want *ClientError")
+		}
+		if srv.Requests() != 1 {
+			t.Errorf("got %d requests, want 1", srv.Requests())
+		}
+	})
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := woodpecker.RetryPolicy{
+		MaxRetries: 10,
+		WaitMin:    time.Second,
+		WaitMax:    30 * time.Second,
+	}
+
+	tests := []struct {
+		name       string
+		retry      int
+		retryAfter string
+		// the backoff with jitter is expected to be in [wantMin, wantMax]
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "OK: first retry",
+			retry:   0,
+			wantMin: 500 * time.Millisecond,
+			wantMax: time.Second,
+		},
+		{
+			name:    "OK: third retry",
+			retry:   2,
+			wantMin: 2 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name:    "OK: capped at WaitMax",
+			retry:   5,
+			wantMin: 15 * time.Second,
+			wantMax: 30 * time.Second,
+		},
+		{
+			name:    "OK: capped at WaitMax after many retries",
+			retry:   1000,
+			wantMin: 15 * time.Second,
+			wantMax: 30 * time.Second,
+		},
+		{
+			name:       "OK: Retry-After in seconds",
+			retry:      0,
+			retryAfter: "10",
+			wantMin:    10 * time.Second,
+			wantMax:    10 * time.Second,
+		},
+		{
+			name:       "OK: Retry-After capped at WaitMax",
+			retry:      0,
+			retryAfter: "3600",
+			wantMin:    30 * time.Second,
+			wantMax:    30 * time.Second,
+		},
+		{
+			name:       "OK: invalid Retry-After ignored",
+			retry:      2,
+			retryAfter: "soon",
+			wantMin:    2 * time.Second,
+			wantMax:    4 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{Header: make(http.Header)}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			// jitter is random, so the bounds are checked on many samples
+			for range 100 {
+				got := policy.Backoff(tt.retry, resp)
+				if got < tt.wantMin || got > tt.wantMax {
+					t.Fatalf("got backoff %s, want between %s and %s", got, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "OK: seconds",
+			value:  "120",
+			want:   2 * time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "OK: zero seconds",
+			value:  "0",
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "OK: HTTP date",
+			value:  now.Add(90 * time.Second).Format(http.TimeFormat),
+			want:   90 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "OK: HTTP date in the past",
+			value:  now.Add(-time.Hour).Format(http.TimeFormat),
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "OK: RFC 850 date",
+			value:  now.Add(time.Minute).Format(time.RFC850),
+			want:   time.Minute,
+			wantOK: true,
+		},
+		{
+			name:   "ERR: empty",
+			value:  "",
+			wantOK: false,
+		},
+		{
+			name:   "ERR: negative seconds",
+			value:  "-1",
+			wantOK: false,
+		},
+		{
+			name:   "ERR: invalid value",
+			value:  "soon",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := woodpecker.ParseRetryAfter(tt.value, now)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("got (%s, %t), want (%s, %t)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
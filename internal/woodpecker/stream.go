@@ -0,0 +1,272 @@
+package woodpecker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	pathStreamEvents = "%s/api/stream/events"
+	pathStreamLogs   = "%s/api/stream/logs/%d/%d/%d"
+)
+
+// streamEventError is the type of the message the server sends when a stream ends or fails.
+// The log stream ends with an error message whose data is streamEOF.
+const (
+	streamEventError = "error"
+	streamEOF        = "eof"
+)
+
+// Event is a pipeline update pushed by the server on the event stream.
+type Event struct {
+	Repo     Repo     `json:"repo"`
+	Pipeline Pipeline `json:"pipeline"`
+}
+
+// sseMessage is a single message of a server-sent event stream.
+type sseMessage struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// StreamEvents returns an iterator over the pipeline updates pushed by the server.
+// The stream never ends on its own, it stops when the client's context is canceled
+// or when the consumer breaks out of the loop.
+func (c *client) StreamEvents() iter.Seq2[*Event, error] {
+	uri := fmt.Sprintf(pathStreamEvents, c.addr)
+	return func(yield func(*Event, error) bool) {
+		for msg, err := range c.stream(uri) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if msg.Data == "" {
+				continue
+			}
+
+			event := new(Event)
+			if err := json.Unmarshal([]byte(msg.Data), event); err != nil {
+				yield(nil, fmt.Errorf("couldn't decode event: %w", err))
+				return
+			}
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// StreamLogs returns an iterator over the log entries of the given step.
+// The server sends the entries stored so far first and then follows the step
+// until it finishes, the stream ends after the last entry.
+func (c *client) StreamLogs(repoID, pipeline, stepID int64) iter.Seq2[*LogEntry, error] {
+	uri := fmt.Sprintf(pathStreamLogs, c.addr, repoID, pipeline, stepID)
+	return func(yield func(*LogEntry, error) bool) {
+		// the server replays the whole log on reconnect,
+		// entries that have already been yielded are skipped
+		var lastID int64
+		for msg, err := range c.stream(uri) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if msg.Data == "" {
+				continue
+			}
+
+			entry := new(LogEntry)
+			if err := json.Unmarshal([]byte(msg.Data), entry); err != nil {
+				yield(nil, fmt.Errorf("couldn't decode log entry: %w", err))
+				return
+			}
+			if entry.ID != 0 && entry.ID <= lastID {
+				continue
+			}
+			lastID = max(lastID, entry.ID)
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
+
+// stream returns an iterator over the messages of the server-sent event stream at rawURL.
+//
+// If the connection drops, the client reconnects according to its RetryPolicy and sends
+// the id of the last received message in the Last-Event-ID header, so that the server can resume the stream.
+// The retry counter is reset every time a message is received. The stream ends without an error
+// when the server sends an error message with the "eof" data, other error messages are yielded as errors.
+// When the context is canceled, its error is yielded.
+func (c *client) stream(rawURL string) iter.Seq2[*sseMessage, error] {
+	return func(yield func(*sseMessage, error) bool) {
+		ctx := c.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		var lastEventID string
+		var serverRetry time.Duration
+		for retry := 0; ; retry++ {
+			resp, err := c.openStream(ctx, rawURL, lastEventID)
+
+			if err == nil {
+				received := false
+				err = readSSE(resp.Body, func(msg *sseMessage) bool {
+					received = true
+					if msg.ID != "" {
+						lastEventID = msg.ID
+					}
+					if msg.Retry > 0 {
+						serverRetry = msg.Retry
+					}
+					if msg.Event == streamEventError {
+						if msg.Data != streamEOF {
+							yield(nil, fmt.Errorf("stream error: %s", msg.Data))
+						}
+						return false
+					}
+					return yield(msg, nil)
+				})
+				resp.Body.Close()
+				if errors.Is(err, errStopStream) {
+					return
+				}
+				if received {
+					retry = 0
+				}
+				if err == nil {
+					// the server closed the stream
+					err = io.ErrUnexpectedEOF
+				}
+			}
+
+			if ctx.Err() != nil {
+				yield(nil, ctx.Err())
+				return
+			}
+			if retry >= c.retry.MaxRetries || !c.shouldReconnect(ctx, err) {
+				yield(nil, err)
+				return
+			}
+			if err := sleepContext(ctx, max(serverRetry, c.retry.backoff(retry, nil))); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+// openStream sends a single request to the stream endpoint.
+// Unlike open, it doesn't retry failed requests, stream handles reconnecting.
+func (c *client) openStream(ctx context.Context, rawURL, lastEventID string) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	// the stream is long-lived, the client's timeout would cut it off,
+	// the stream ends when ctx is canceled instead
+	cli := *c.client
+	cli.Timeout = 0
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode > http.StatusPartialContent {
+		defer resp.Body.Close()
+		out, _ := io.ReadAll(resp.Body)
+		return nil, &ClientError{
+			StatusCode: resp.StatusCode,
+			Message:    string(out),
+		}
+	}
+	return resp, nil
+}
+
+// shouldReconnect reports whether the stream should be reopened after err according to RetryPolicy.
+func (c *client) shouldReconnect(ctx context.Context, err error) bool {
+	var clientErr *ClientError
+	if errors.As(err, &clientErr) {
+		return c.retry.shouldRetry(ctx, http.MethodGet, &http.Response{StatusCode: clientErr.StatusCode}, nil)
+	}
+	return c.retry.shouldRetry(ctx, http.MethodGet, nil, err)
+}
+
+// errStopStream is returned by readSSE when the handler asks to stop reading.
+var errStopStream = errors.New("stream stopped")
+
+// readSSE reads messages from r and passes them to handle until r is exhausted,
+// reading fails or handle returns false.
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation.
+func readSSE(r io.Reader, handle func(msg *sseMessage) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	msg := new(sseMessage)
+	var data strings.Builder
+	hasData := false
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if hasData || msg.Event != "" {
+				msg.Data = data.String()
+				if !handle(msg) {
+					return errStopStream
+				}
+			}
+			msg = new(sseMessage)
+			data.Reset()
+			hasData = false
+			continue
+		}
+
+		// lines starting with a colon are comments, the server uses them as keep-alive pings
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "event":
+			msg.Event = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				msg.ID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseInt(value, 10, 64); err == nil && ms >= 0 {
+				msg.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	return scanner.Err()
+}
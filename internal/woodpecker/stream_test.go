@@ -0,0 +1,197 @@
+package woodpecker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker/woodpeckertest"
+)
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	policy := woodpecker.RetryPolicy{
+		MaxRetries: 3,
+		WaitMin:    10 * time.Millisecond,
+		WaitMax:    100 * time.Millisecond,
+	}
+
+	t.Run("OK: events", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		client := srv.Client()
+		repo := activateFakeRepo(t, srv)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		events, errs := collectStream(client.WithContext(ctx).StreamEvents())
+		waitForStreams(t, srv, 1)
+
+		pipeline, err := client.PipelineCreate(repo.ID, &woodpecker.PipelineOptions{Branch: repo.Branch})
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv.SetPipelineStatus(repo.ID, pipeline.Number, woodpecker.StatusSuccess)
+
+		for _, want := range []string{woodpecker.StatusPending, woodpecker.StatusSuccess} {
+			event := receive(t, events)
+			if event.Repo.ID != repo.ID || event.Pipeline.Number != pipeline.Number {
+				t.Errorf("got event for %s#%d, want %s#%d",
+					event.Repo.FullName, event.Pipeline.Number, repo.FullName, pipeline.Number)
+			}
+			if event.Pipeline.Status != want {
+				t.Errorf("got status %s, want %s", event.Pipeline.Status, want)
+			}
+		}
+
+		cancel()
+		if err := receive(t, errs); !errors.Is(err, context.Canceled) {
+			t.Errorf("got error %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("OK: events resumed after reconnect", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		client := srv.Client()
+		client.SetRetryPolicy(policy)
+		repo := activateFakeRepo(t, srv)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		events, _ := collectStream(client.WithContext(ctx).StreamEvents())
+		waitForStreams(t, srv, 1)
+
+		pipeline, err := client.PipelineCreate(repo.ID, &woodpecker.PipelineOptions{Branch: repo.Branch})
+		if err != nil {
+			t.Fatal(err)
+		}
+		receive(t, events)
+
+		// the update is published while the client is disconnected
+		// and is replayed thanks to Last-Event-ID
+		srv.DropStreams()
+		srv.SetPipelineStatus(repo.ID, pipeline.Number, woodpecker.StatusFailure)
+
+		if event := receive(t, events); event.Pipeline.Status != woodpecker.StatusFailure {
+			t.Errorf("got status %s, want %s", event.Pipeline.Status, woodpecker.StatusFailure)
+		}
+	})
+
+	t.Run("OK: logs", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		client := srv.Client()
+		repo := activateFakeRepo(t, srv)
+
+		pipeline, err := client.PipelineCreate(repo.ID, &woodpecker.PipelineOptions{Branch: repo.Branch})
+		if err != nil {
+			t.Fatal(err)
+		}
+		step := pipeline.Workflows[0].Children[1]
+		srv.AddStepLog(repo.ID, pipeline.Number, step.Name, woodpecker.LogEntry{Data: []byte("line 1")})
+
+		entries, errs := collectStream(client.WithContext(t.Context()).StreamLogs(repo.ID, pipeline.Number, step.ID))
+
+		if entry := receive(t, entries); string(entry.Data) != "line 1" {
+			t.Errorf("got %q, want %q", entry.Data, "line 1")
+		}
+
+		srv.AddStepLog(repo.ID, pipeline.Number, step.Name, woodpecker.LogEntry{Data: []byte("line 2")})
+		if entry := receive(t, entries); string(entry.Data) != "line 2" {
+			t.Errorf("got %q, want %q", entry.Data, "line 2")
+		}
+
+		// the stream ends without an error once the step finishes
+		srv.SetPipelineStatus(repo.ID, pipeline.Number, woodpecker.StatusSuccess)
+		if err := receive(t, errs); err != nil {
+			t.Errorf("got error %v, want nil", err)
+		}
+	})
+
+	t.Run("ERR: step not found", func(t *testing.T) {
+		t.Parallel()
+
+		srv := woodpeckertest.NewServer()
+		t.Cleanup(srv.Close)
+		client := srv.Client()
+		client.SetRetryPolicy(policy)
+		repo := activateFakeRepo(t, srv)
+
+		pipeline, err := client.PipelineCreate(repo.ID, &woodpecker.PipelineOptions{Branch: repo.Branch})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, err := range client.StreamLogs(repo.ID, pipeline.Number, 0) {
+			if !errors.Is(err, woodpecker.ErrNotFound) {
+				t.Errorf("got error %v, want %v", err, woodpecker.ErrNotFound)
+			}
+		}
+		if srv.Requests() != 3 {
+			t.Errorf("got %d requests, want 3", srv.Requests())
+		}
+	})
+}
+
+func activateFakeRepo(tb testing.TB, srv *woodpeckertest.Server) *woodpecker.Repo {
+	tb.Helper()
+
+	forgeRepo := srv.AddForgeRepo("owner", "repo")
+	repo, err := srv.Client().RepoPost(woodpecker.RepoPostOptions{ForgeRemoteID: forgeRepo.ForgeRemoteID})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return repo
+}
+
+// collectStream consumes the stream in the background.
+// Items are sent on the first channel, the error that ended the stream (or nil) on the second.
+func collectStream[T any](seq func(yield func(T, error) bool)) (<-chan T, <-chan error) {
+	items := make(chan T, 10)
+	errs := make(chan error, 1)
+	go func() {
+		for item, err := range seq {
+			if err != nil {
+				errs <- err
+				return
+			}
+			items <- item
+		}
+		errs <- nil
+	}()
+	return items, errs
+}
+
+func receive[T any](tb testing.TB, ch <-chan T) T {
+	tb.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		tb.Fatal("timed out waiting for the stream")
+		var zero T
+		return zero
+	}
+}
+
+func waitForStreams(tb testing.TB, srv *woodpeckertest.Server, n int) {
+	tb.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.OpenStreams() != n {
+		if time.Now().After(deadline) {
+			tb.Fatalf("got %d open streams, want %d", srv.OpenStreams(), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -92,6 +92,11 @@ type (
 		Config                       string               `json:"config_file"`
 		CancelPreviousPipelineEvents []string             `json:"cancel_previous_pipeline_events"`
 		NetrcTrustedPlugins          []string             `json:"netrc_trusted"`
+		ConfigExtensionEndpoint      string               `json:"config_extension_endpoint"`
+		RegistryExtensionEndpoint    string               `json:"registry_extension_endpoint"`
+		SecretExtensionEndpoint      string               `json:"secret_extension_endpoint"`
+		MaxPipelineAge               int64                `json:"max_pipeline_age"`
+		LogExpiry                    int64                `json:"log_expiry"`
 	}
 
 	TrustedConfigurationPatch struct {
@@ -112,6 +117,11 @@ type (
 		NetrcTrustedPlugins          []string                   `json:"netrc_trusted,omitempty"`
 		ApprovalAllowedUsers         []string                   `json:"approval_allowed_users,omitempty"`
 		Trusted                      *TrustedConfigurationPatch `json:"trusted,omitempty"`
+		ConfigExtensionEndpoint      *string                    `json:"config_extension_endpoint,omitempty"`
+		RegistryExtensionEndpoint    *string                    `json:"registry_extension_endpoint,omitempty"`
+		SecretExtensionEndpoint      *string                    `json:"secret_extension_endpoint,omitempty"`
+		MaxPipelineAge               *int64                     `json:"max_pipeline_age,omitempty"`
+		LogExpiry                    *int64                     `json:"log_expiry,omitempty"`
 	}
 
 	PipelineError struct {
@@ -315,4 +325,18 @@ type (
 		Name    string `json:"name"`
 		IsUser  bool   `json:"is_user"`
 	}
+
+	// Perm is the permission of the current user in a repository.
+	Perm struct {
+		Pull   bool  `json:"pull"`
+		Push   bool  `json:"push"`
+		Admin  bool  `json:"admin"`
+		Synced int64 `json:"synced"`
+	}
+
+	// OrgPerm is the permission of the current user in an organization.
+	OrgPerm struct {
+		Member bool `json:"member"`
+		Admin  bool `json:"admin"`
+	}
 )
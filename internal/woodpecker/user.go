@@ -79,6 +79,7 @@ func (c *client) UserDel(login string) error {
 
 // RepoList returns a list of all repositories to which
 // the user has explicit access in the host system.
+// The endpoint isn't paginated, all repositories are returned at once.
 func (c *client) RepoList(opt RepoListOptions) ([]*Repo, error) {
 	var out []*Repo
 	uri, _ := url.Parse(fmt.Sprintf(pathRepos, c.addr))
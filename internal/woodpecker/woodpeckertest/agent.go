@@ -0,0 +1,164 @@
+package woodpeckertest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+// globalAgentOrgID is the org id the server assigns to agents that don't belong to any organization.
+const globalAgentOrgID = -1
+
+// handleAgents registers the agent endpoints under the given prefix.
+// Agents of all organizations are accessible through the global scope.
+func (s *Server) handleAgents(prefix string, scopeFn scopeFunc) {
+	s.mux.HandleFunc("GET "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		sc, ok := scopeFn(w, r)
+		if !ok {
+			return
+		}
+
+		out := make([]*woodpecker.Agent, 0, len(s.agents))
+		for _, agent := range s.agents {
+			if agentInScope(agent, sc) {
+				out = append(out, agent)
+			}
+		}
+		writeJSON(w, http.StatusOK, paginate(r, out))
+	})
+
+	s.mux.HandleFunc("POST "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		var in woodpecker.Agent
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		sc, ok := scopeFn(w, r)
+		if !ok {
+			return
+		}
+
+		if in.Name == "" {
+			http.Error(w, "Error inserting agent. Name is required", http.StatusBadRequest)
+			return
+		}
+
+		orgID := sc.orgID
+		if orgID == 0 {
+			orgID = globalAgentOrgID
+		}
+
+		now := time.Now().Unix()
+		agent := &woodpecker.Agent{
+			ID:           s.nextID(),
+			Created:      now,
+			Updated:      now,
+			Name:         in.Name,
+			OwnerID:      s.findUser(SelfLogin).ID,
+			OrgID:        orgID,
+			Token:        newToken(),
+			NoSchedule:   in.NoSchedule,
+			CustomLabels: in.CustomLabels,
+		}
+		s.agents = append(s.agents, agent)
+		writeJSON(w, http.StatusOK, agent)
+	})
+
+	s.mux.HandleFunc("PATCH "+prefix+"/{agent_id}", func(w http.ResponseWriter, r *http.Request) {
+		var in woodpecker.Agent
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		agent, ok := s.pathAgent(w, r, scopeFn)
+		if !ok {
+			return
+		}
+
+		if in.Name != "" {
+			agent.Name = in.Name
+		}
+		agent.NoSchedule = in.NoSchedule
+		agent.CustomLabels = in.CustomLabels
+		agent.Updated = time.Now().Unix()
+		writeJSON(w, http.StatusOK, agent)
+	})
+
+	s.mux.HandleFunc("DELETE "+prefix+"/{agent_id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		agent, ok := s.pathAgent(w, r, scopeFn)
+		if !ok {
+			return
+		}
+		s.agents = slices.DeleteFunc(s.agents, func(a *woodpecker.Agent) bool {
+			return a == agent
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (s *Server) getAgent(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, ok := s.pathAgent(w, r, globalScope)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, agent)
+}
+
+func (s *Server) listAgentTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pathAgent(w, r, globalScope); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, make([]*woodpecker.Task, 0))
+}
+
+// pathAgent returns the agent identified by the request scope and the agent_id path value.
+// It must be called with s.mu held.
+func (s *Server) pathAgent(w http.ResponseWriter, r *http.Request, scopeFn scopeFunc) (*woodpecker.Agent, bool) {
+	sc, ok := scopeFn(w, r)
+	if !ok {
+		return nil, false
+	}
+	id, ok := pathID(w, r, "agent_id")
+	if !ok {
+		return nil, false
+	}
+	for _, agent := range s.agents {
+		if agent.ID == id && agentInScope(agent, sc) {
+			return agent, true
+		}
+	}
+	http.Error(w, "Agent not found", http.StatusNotFound)
+	return nil, false
+}
+
+func agentInScope(agent *woodpecker.Agent, sc scope) bool {
+	return sc.orgID == 0 || agent.OrgID == sc.orgID
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
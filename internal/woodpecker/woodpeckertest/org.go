@@ -0,0 +1,88 @@
+package woodpeckertest
+
+import (
+	"net/http"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+func (s *Server) listOrgs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginate(r, s.orgs))
+}
+
+func (s *Server) getOrg(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "org_id")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	org := s.findOrg(id)
+	if org == nil {
+		http.Error(w, "Organization not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, org)
+}
+
+// getOrgPermissions reports SelfLogin as an admin of every organization,
+// the fake forge gives it access to all repositories.
+func (s *Server) getOrgPermissions(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "org_id")
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findOrg(id) == nil {
+		http.Error(w, "Organization not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, &woodpecker.OrgPerm{Member: true, Admin: true})
+}
+
+func (s *Server) orgLookup(w http.ResponseWriter, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, org := range s.orgs {
+		if org.Name == name {
+			writeJSON(w, http.StatusOK, org)
+			return
+		}
+	}
+	http.Error(w, "Organization not found", http.StatusNotFound)
+}
+
+func (s *Server) findOrg(id int64) *woodpecker.Org {
+	for _, org := range s.orgs {
+		if org.ID == id {
+			return org
+		}
+	}
+	return nil
+}
+
+// ensureOrg returns the organization with the given name, creating it if it doesn't exist yet.
+func (s *Server) ensureOrg(name string) *woodpecker.Org {
+	for _, org := range s.orgs {
+		if org.Name == name {
+			return org
+		}
+	}
+
+	org := &woodpecker.Org{
+		ID:      s.nextID(),
+		ForgeID: 1,
+		Name:    name,
+		IsUser:  s.findUser(name) != nil,
+	}
+	s.orgs = append(s.orgs, org)
+	return org
+}
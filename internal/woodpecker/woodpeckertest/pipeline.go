@@ -0,0 +1,373 @@
+package woodpeckertest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+// SetPipelineStatus sets the status of the pipeline and of all its workflows and steps.
+// It's meant to simulate agents running pipelines, which the fake server doesn't have.
+func (s *Server) SetPipelineStatus(repoID, number int64, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pipeline := s.findPipeline(repoID, number); pipeline != nil {
+		setPipelineStatus(pipeline, status)
+		s.publishPipeline(s.findRepo(repoID), pipeline)
+	}
+}
+
+// AddStepLog appends entries to the log of the given step.
+// ID, StepID, Line and Time of the entries are set by the server.
+func (s *Server) AddStepLog(repoID, number int64, stepName string, entries ...woodpecker.LogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pipeline := s.findPipeline(repoID, number)
+	if pipeline == nil {
+		return
+	}
+	s.addStepLog(pipeline, stepName, entries)
+	s.broadcast()
+}
+
+// FinishPipelines makes the server finish every pipeline created in the given repo from now on
+// with the given status, the build step gets the given log entries.
+// Like SetPipelineStatus, it's meant to simulate agents, for pipelines started by the code under test.
+func (s *Server) FinishPipelines(repoID int64, status string, entries ...woodpecker.LogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runs[repoID] = pipelineRun{status: status, entries: entries}
+}
+
+type pipelineRun struct {
+	status  string
+	entries []woodpecker.LogEntry
+}
+
+// addStepLog appends entries to the log of the given step.
+// It must be called with s.mu held.
+func (s *Server) addStepLog(pipeline *woodpecker.Pipeline, stepName string, entries []woodpecker.LogEntry) {
+	step := findStep(pipeline, func(step *woodpecker.Step) bool {
+		return step.Name == stepName
+	})
+	if step == nil {
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, entry := range entries {
+		entry.ID = s.nextID()
+		entry.StepID = step.ID
+		entry.Line = len(s.logs[step.ID])
+		entry.Time = now
+		s.logs[step.ID] = append(s.logs[step.ID], &entry)
+	}
+}
+
+func setPipelineStatus(pipeline *woodpecker.Pipeline, status string) {
+	now := time.Now().Unix()
+	pipeline.Status = status
+	pipeline.Updated = now
+	if status != woodpecker.StatusPending && pipeline.Started == 0 {
+		pipeline.Started = now
+	}
+	if isFinished(status) {
+		pipeline.Finished = now
+	}
+
+	for _, workflow := range pipeline.Workflows {
+		workflow.State = status
+		if isFinished(status) {
+			workflow.Stopped = now
+		}
+		for _, step := range workflow.Children {
+			step.State = status
+			if status == woodpecker.StatusFailure {
+				step.ExitCode = 1
+			}
+			if isFinished(status) {
+				step.Stopped = now
+			}
+		}
+	}
+}
+
+func (s *Server) listPipelines(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	out := make([]*woodpecker.Pipeline, 0, len(s.pipelines[repo.ID]))
+	// the newest pipelines come first
+	for _, pipeline := range slices.Backward(s.pipelines[repo.ID]) {
+		if branch := query.Get("branch"); branch != "" && pipeline.Branch != branch {
+			continue
+		}
+		if status := query.Get("status"); status != "" && pipeline.Status != status {
+			continue
+		}
+		if events := query.Get("event"); events != "" && !slices.Contains(strings.Split(events, ","), pipeline.Event) {
+			continue
+		}
+		out = append(out, pipelineWithoutWorkflows(pipeline))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) getPipeline(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	if r.PathValue("number") == "latest" {
+		branch := r.URL.Query().Get("branch")
+		if branch == "" {
+			branch = repo.Branch
+		}
+		for _, pipeline := range slices.Backward(s.pipelines[repo.ID]) {
+			if pipeline.Branch == branch {
+				writeJSON(w, http.StatusOK, pipeline)
+				return
+			}
+		}
+		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		return
+	}
+
+	pipeline, ok := s.pathPipeline(w, r, repo)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, pipeline)
+}
+
+func (s *Server) createPipeline(w http.ResponseWriter, r *http.Request) {
+	var in woodpecker.PipelineOptions
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	if in.Branch == "" {
+		http.Error(w, "Branch is required", http.StatusBadRequest)
+		return
+	}
+
+	pipeline := s.newPipeline(repo, woodpecker.EventManual, in.Branch, newToken()[:40])
+	s.publishPipeline(repo, pipeline)
+	writeJSON(w, http.StatusOK, pipeline)
+}
+
+// restartPipeline starts a new pipeline for the same commit as the given one.
+// The event query param can be used to turn it into a deployment.
+func (s *Server) restartPipeline(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+	parent, ok := s.pathPipeline(w, r, repo)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	event := parent.Event
+	if e := query.Get("event"); e != "" {
+		event = e
+	}
+	if event == woodpecker.EventDeploy && !repo.AllowDeployments {
+		http.Error(w, "Deployments are not allowed for this repository", http.StatusForbidden)
+		return
+	}
+
+	pipeline := s.newPipeline(repo, event, parent.Branch, parent.Commit)
+	pipeline.Parent = parent.Number
+	pipeline.Ref = parent.Ref
+	pipeline.Message = parent.Message
+	if event == woodpecker.EventDeploy {
+		pipeline.Deploy = query.Get("deploy_to")
+	}
+	s.publishPipeline(repo, pipeline)
+	writeJSON(w, http.StatusOK, pipeline)
+}
+
+func (s *Server) cancelPipeline(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+	pipeline, ok := s.pathPipeline(w, r, repo)
+	if !ok {
+		return
+	}
+
+	if !isFinished(pipeline.Status) {
+		setPipelineStatus(pipeline, woodpecker.StatusKilled)
+		s.publishPipeline(repo, pipeline)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getStepLogs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	step, ok := s.pathStep(w, r)
+	if !ok {
+		return
+	}
+
+	out := s.logs[step.ID]
+	if out == nil {
+		out = make([]*woodpecker.LogEntry, 0)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// newPipeline creates a pending pipeline with a single workflow.
+// It must be called with s.mu held.
+func (s *Server) newPipeline(repo *woodpecker.Repo, event, branch, commit string) *woodpecker.Pipeline {
+	now := time.Now().Unix()
+	pipeline := &woodpecker.Pipeline{
+		ID:        s.nextID(),
+		Number:    int64(len(s.pipelines[repo.ID]) + 1),
+		Event:     event,
+		Status:    woodpecker.StatusPending,
+		Errors:    make([]*woodpecker.PipelineError, 0),
+		Created:   now,
+		Updated:   now,
+		Commit:    commit,
+		Branch:    branch,
+		Ref:       "refs/heads/" + branch,
+		Message:   "manual pipeline",
+		Timestamp: now,
+		Sender:    SelfLogin,
+		Author:    SelfLogin,
+		ForgeURL:  repo.ForgeURL + "/commit/" + commit,
+		Workflows: []*woodpecker.Workflow{
+			{
+				ID:    s.nextID(),
+				PID:   1,
+				Name:  "woodpecker",
+				State: woodpecker.StatusPending,
+				Children: []*woodpecker.Step{
+					{
+						ID:    s.nextID(),
+						PID:   2,
+						PPID:  1,
+						Name:  "clone",
+						State: woodpecker.StatusPending,
+						Type:  woodpecker.StepTypeClone,
+					},
+					{
+						ID:    s.nextID(),
+						PID:   3,
+						PPID:  1,
+						Name:  "build",
+						State: woodpecker.StatusPending,
+						Type:  woodpecker.StepTypeCommands,
+					},
+				},
+			},
+		},
+	}
+	s.pipelines[repo.ID] = append(s.pipelines[repo.ID], pipeline)
+
+	if run, ok := s.runs[repo.ID]; ok {
+		s.addStepLog(pipeline, "build", run.entries)
+		setPipelineStatus(pipeline, run.status)
+	}
+
+	return pipeline
+}
+
+// pathPipeline returns the pipeline identified by the number path value.
+// It must be called with s.mu held.
+func (s *Server) pathPipeline(
+	w http.ResponseWriter,
+	r *http.Request,
+	repo *woodpecker.Repo,
+) (*woodpecker.Pipeline, bool) {
+	number, err := strconv.ParseInt(r.PathValue("number"), 10, 64)
+	if err != nil {
+		http.Error(w, "Error parsing pipeline number. "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	pipeline := s.findPipeline(repo.ID, number)
+	if pipeline == nil {
+		http.Error(w, "Pipeline not found", http.StatusNotFound)
+		return nil, false
+	}
+	return pipeline, true
+}
+
+func (s *Server) findPipeline(repoID, number int64) *woodpecker.Pipeline {
+	for _, pipeline := range s.pipelines[repoID] {
+		if pipeline.Number == number {
+			return pipeline
+		}
+	}
+	return nil
+}
+
+func findStep(pipeline *woodpecker.Pipeline, match func(step *woodpecker.Step) bool) *woodpecker.Step {
+	for _, workflow := range pipeline.Workflows {
+		for _, step := range workflow.Children {
+			if match(step) {
+				return step
+			}
+		}
+	}
+	return nil
+}
+
+// pipelineWithoutWorkflows returns a copy of the pipeline without workflows,
+// list endpoints don't return them.
+func pipelineWithoutWorkflows(pipeline *woodpecker.Pipeline) *woodpecker.Pipeline {
+	cp := *pipeline
+	cp.Workflows = nil
+	return &cp
+}
+
+func isFinished(status string) bool {
+	switch status {
+	case woodpecker.StatusSuccess,
+		woodpecker.StatusFailure,
+		woodpecker.StatusKilled,
+		woodpecker.StatusError,
+		woodpecker.StatusSkipped,
+		woodpecker.StatusDeclined:
+		return true
+	default:
+		return false
+	}
+}
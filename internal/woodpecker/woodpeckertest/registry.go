@@ -0,0 +1,151 @@
+package woodpeckertest
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+// handleRegistries registers the registry endpoints under the given prefix.
+func (s *Server) handleRegistries(prefix string, scopeFn scopeFunc) {
+	s.mux.HandleFunc("GET "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		sc, ok := scopeFn(w, r)
+		if !ok {
+			return
+		}
+
+		out := make([]*woodpecker.Registry, 0, len(s.registries))
+		for _, registry := range s.registries {
+			if registryScope(registry) == sc {
+				out = append(out, redactRegistry(registry))
+			}
+		}
+		writeJSON(w, http.StatusOK, paginate(r, out))
+	})
+
+	s.mux.HandleFunc("POST "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		var in woodpecker.Registry
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		sc, ok := scopeFn(w, r)
+		if !ok {
+			return
+		}
+
+		if in.Address == "" || in.Username == "" || in.Password == "" {
+			http.Error(w, "Error inserting registry. Address, username and password are required", http.StatusBadRequest)
+			return
+		}
+		if s.findRegistry(sc, in.Address) != nil {
+			http.Error(w, "Registry already exists", http.StatusConflict)
+			return
+		}
+
+		registry := &woodpecker.Registry{
+			ID:       s.nextID(),
+			OrgID:    sc.orgID,
+			RepoID:   sc.repoID,
+			Address:  in.Address,
+			Username: in.Username,
+			Password: in.Password,
+		}
+		s.registries = append(s.registries, registry)
+		writeJSON(w, http.StatusOK, redactRegistry(registry))
+	})
+
+	s.mux.HandleFunc("GET "+prefix+"/{address}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		registry, ok := s.pathRegistry(w, r, scopeFn)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, redactRegistry(registry))
+	})
+
+	s.mux.HandleFunc("PATCH "+prefix+"/{address}", func(w http.ResponseWriter, r *http.Request) {
+		var in woodpecker.Registry
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		registry, ok := s.pathRegistry(w, r, scopeFn)
+		if !ok {
+			return
+		}
+
+		if in.Username != "" {
+			registry.Username = in.Username
+		}
+		if in.Password != "" {
+			registry.Password = in.Password
+		}
+		writeJSON(w, http.StatusOK, redactRegistry(registry))
+	})
+
+	s.mux.HandleFunc("DELETE "+prefix+"/{address}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		registry, ok := s.pathRegistry(w, r, scopeFn)
+		if !ok {
+			return
+		}
+		s.registries = slices.DeleteFunc(s.registries, func(reg *woodpecker.Registry) bool {
+			return reg == registry
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// pathRegistry returns the registry identified by the request scope and the address path value.
+// It must be called with s.mu held.
+func (s *Server) pathRegistry(
+	w http.ResponseWriter,
+	r *http.Request,
+	scopeFn scopeFunc,
+) (*woodpecker.Registry, bool) {
+	sc, ok := scopeFn(w, r)
+	if !ok {
+		return nil, false
+	}
+	registry := s.findRegistry(sc, r.PathValue("address"))
+	if registry == nil {
+		http.Error(w, "Registry not found", http.StatusNotFound)
+		return nil, false
+	}
+	return registry, true
+}
+
+func (s *Server) findRegistry(sc scope, address string) *woodpecker.Registry {
+	for _, registry := range s.registries {
+		if registryScope(registry) == sc && registry.Address == address {
+			return registry
+		}
+	}
+	return nil
+}
+
+func registryScope(registry *woodpecker.Registry) scope {
+	return scope{orgID: registry.OrgID, repoID: registry.RepoID}
+}
+
+// redactRegistry returns a copy of the registry without its password, the API never returns passwords.
+func redactRegistry(registry *woodpecker.Registry) *woodpecker.Registry {
+	cp := *registry
+	cp.Password = ""
+	return &cp
+}
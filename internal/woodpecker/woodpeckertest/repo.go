@@ -0,0 +1,408 @@
+package woodpeckertest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+func (s *Server) listUserRepos(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all := r.URL.Query().Get("all") == "true"
+	out := make([]*woodpecker.Repo, 0, len(s.repos))
+	for _, repo := range s.repos {
+		switch {
+		case all && s.forgeNames[repo.ForgeRemoteID] != "":
+			// the forge already knows the new name of the repository
+			cp := *repo
+			cp.FullName = s.forgeNames[repo.ForgeRemoteID]
+			cp.Owner, cp.Name, _ = strings.Cut(cp.FullName, "/")
+			out = append(out, &cp)
+		case all || repo.IsActive:
+			out = append(out, repo)
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) getRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+// getRepoPermissions reports SelfLogin as an admin of every repository,
+// the fake forge gives it access to all repositories.
+func (s *Server) getRepoPermissions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pathRepo(w, r); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, &woodpecker.Perm{
+		Pull:   true,
+		Push:   true,
+		Admin:  true,
+		Synced: time.Now().Unix(),
+	})
+}
+
+func (s *Server) repoLookup(w http.ResponseWriter, fullName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, repo := range s.repos {
+		if repo.ID != 0 && strings.EqualFold(repo.FullName, fullName) {
+			writeJSON(w, http.StatusOK, repo)
+			return
+		}
+	}
+	http.Error(w, "Repository not found", http.StatusNotFound)
+}
+
+func (s *Server) activateRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	forgeRemoteID := r.URL.Query().Get("forge_remote_id")
+	forgeID := r.URL.Query().Get("forge_id")
+	idx := slices.IndexFunc(s.repos, func(repo *woodpecker.Repo) bool {
+		return repo.ForgeRemoteID == forgeRemoteID && (forgeID == "" || forgeID == strconv.FormatInt(repo.ForgeID, 10))
+	})
+	if idx < 0 {
+		http.Error(w, "Could not fetch repository from forge", http.StatusNotFound)
+		return
+	}
+
+	repo := s.repos[idx]
+	if repo.IsActive {
+		http.Error(w, "Repository is already active", http.StatusConflict)
+		return
+	}
+
+	if repo.ID == 0 {
+		repo.ID = s.nextID()
+	}
+	repo.IsActive = true
+	s.ensureOrg(repo.Owner)
+
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) updateRepo(w http.ResponseWriter, r *http.Request) {
+	var in woodpecker.RepoPatch
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	if in.Config != nil {
+		repo.Config = *in.Config
+	}
+	if in.RequireApproval != nil {
+		repo.RequireApproval = *in.RequireApproval
+	}
+	if in.Timeout != nil {
+		repo.Timeout = *in.Timeout
+	}
+	if in.Visibility != nil {
+		repo.Visibility = *in.Visibility
+	}
+	if in.AllowPullRequests != nil {
+		repo.AllowPullRequests = *in.AllowPullRequests
+	}
+	if in.AllowDeployments != nil {
+		repo.AllowDeployments = *in.AllowDeployments
+	}
+	if in.CancelPreviousPipelineEvents != nil {
+		repo.CancelPreviousPipelineEvents = in.CancelPreviousPipelineEvents
+	}
+	if in.NetrcTrustedPlugins != nil {
+		repo.NetrcTrustedPlugins = in.NetrcTrustedPlugins
+	}
+	if in.ApprovalAllowedUsers != nil {
+		repo.ApprovalAllowedUsers = in.ApprovalAllowedUsers
+	}
+	if in.Trusted != nil {
+		if in.Trusted.Network != nil {
+			repo.Trusted.Network = *in.Trusted.Network
+		}
+		if in.Trusted.Volumes != nil {
+			repo.Trusted.Volumes = *in.Trusted.Volumes
+		}
+		if in.Trusted.Security != nil {
+			repo.Trusted.Security = *in.Trusted.Security
+		}
+	}
+	if in.ConfigExtensionEndpoint != nil {
+		repo.ConfigExtensionEndpoint = *in.ConfigExtensionEndpoint
+	}
+	if in.RegistryExtensionEndpoint != nil {
+		repo.RegistryExtensionEndpoint = *in.RegistryExtensionEndpoint
+	}
+	if in.SecretExtensionEndpoint != nil {
+		repo.SecretExtensionEndpoint = *in.SecretExtensionEndpoint
+	}
+	if in.MaxPipelineAge != nil {
+		repo.MaxPipelineAge = *in.MaxPipelineAge
+	}
+	if in.LogExpiry != nil {
+		repo.LogExpiry = *in.LogExpiry
+	}
+
+	writeJSON(w, http.StatusOK, repo)
+}
+
+// deleteRepo deletes the repository with all its data, or only deactivates it if the remove query param is false.
+func (s *Server) deleteRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	repo.IsActive = false
+	if r.URL.Query().Get("remove") != "false" {
+		repoID := repo.ID
+		repo.ID = 0
+		s.secrets = slices.DeleteFunc(s.secrets, func(secret *woodpecker.Secret) bool {
+			return secret.RepoID == repoID
+		})
+		s.registries = slices.DeleteFunc(s.registries, func(registry *woodpecker.Registry) bool {
+			return registry.RepoID == repoID
+		})
+		s.crons = slices.DeleteFunc(s.crons, func(cron *woodpecker.Cron) bool {
+			return cron.RepoID == repoID
+		})
+		for _, pipeline := range s.pipelines[repoID] {
+			for _, workflow := range pipeline.Workflows {
+				for _, step := range workflow.Children {
+					delete(s.logs, step.ID)
+				}
+			}
+		}
+		delete(s.pipelines, repoID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) moveRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	owner, name, ok := strings.Cut(r.URL.Query().Get("to"), "/")
+	if !ok || owner == "" || name == "" {
+		http.Error(w, "Missing or invalid 'to' query param", http.StatusBadRequest)
+		return
+	}
+
+	repo.Owner = owner
+	repo.Name = name
+	repo.FullName = owner + "/" + name
+	delete(s.forgeNames, repo.ForgeRemoteID)
+	s.ensureOrg(owner)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) chownRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, repo)
+}
+
+func (s *Server) repairRepo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pathRepo(w, r); !ok {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listCrons(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	out := make([]*woodpecker.Cron, 0, len(s.crons))
+	for _, cron := range s.crons {
+		if cron.RepoID == repo.ID {
+			out = append(out, cron)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) getCron(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cron, ok := s.pathCron(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, cron)
+}
+
+func (s *Server) createCron(w http.ResponseWriter, r *http.Request) {
+	var in woodpecker.Cron
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return
+	}
+
+	if in.Name == "" || in.Schedule == "" {
+		http.Error(w, "Error inserting cron. Name and schedule are required", http.StatusBadRequest)
+		return
+	}
+	for _, cron := range s.crons {
+		if cron.RepoID == repo.ID && cron.Name == in.Name {
+			http.Error(w, "Cron already exists", http.StatusConflict)
+			return
+		}
+	}
+
+	now := time.Now().Unix()
+	cron := &woodpecker.Cron{
+		ID:        s.nextID(),
+		Name:      in.Name,
+		RepoID:    repo.ID,
+		CreatorID: s.findUser(SelfLogin).ID,
+		NextExec:  now,
+		Schedule:  in.Schedule,
+		Created:   now,
+		Branch:    in.Branch,
+	}
+	s.crons = append(s.crons, cron)
+	writeJSON(w, http.StatusOK, cron)
+}
+
+func (s *Server) updateCron(w http.ResponseWriter, r *http.Request) {
+	var in woodpecker.Cron
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cron, ok := s.pathCron(w, r)
+	if !ok {
+		return
+	}
+
+	if in.Name != "" {
+		cron.Name = in.Name
+	}
+	if in.Schedule != "" {
+		cron.Schedule = in.Schedule
+	}
+	cron.Branch = in.Branch
+	writeJSON(w, http.StatusOK, cron)
+}
+
+func (s *Server) deleteCron(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cron, ok := s.pathCron(w, r)
+	if !ok {
+		return
+	}
+	s.crons = slices.DeleteFunc(s.crons, func(c *woodpecker.Cron) bool {
+		return c == cron
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathRepo returns the repository identified by the repo_id path value.
+// It must be called with s.mu held.
+func (s *Server) pathRepo(w http.ResponseWriter, r *http.Request) (*woodpecker.Repo, bool) {
+	id, ok := pathID(w, r, "repo_id")
+	if !ok {
+		return nil, false
+	}
+	for _, repo := range s.repos {
+		if repo.ID != 0 && repo.ID == id {
+			return repo, true
+		}
+	}
+	http.Error(w, "Repository not found", http.StatusNotFound)
+	return nil, false
+}
+
+// pathCron returns the cron identified by the repo_id and cron_id path values.
+// It must be called with s.mu held.
+func (s *Server) pathCron(w http.ResponseWriter, r *http.Request) (*woodpecker.Cron, bool) {
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return nil, false
+	}
+	id, ok := pathID(w, r, "cron_id")
+	if !ok {
+		return nil, false
+	}
+	for _, cron := range s.crons {
+		if cron.RepoID == repo.ID && cron.ID == id {
+			return cron, true
+		}
+	}
+	http.Error(w, "Cron not found", http.StatusNotFound)
+	return nil, false
+}
+
+// findRepo returns the active or deactivated repository with the given id.
+// It must be called with s.mu held.
+func (s *Server) findRepo(id int64) *woodpecker.Repo {
+	for _, repo := range s.repos {
+		if repo.ID != 0 && repo.ID == id {
+			return repo
+		}
+	}
+	return nil
+}
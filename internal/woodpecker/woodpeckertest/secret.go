@@ -0,0 +1,158 @@
+package woodpeckertest
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+// handleSecrets registers the secret endpoints under the given prefix.
+func (s *Server) handleSecrets(prefix string, scopeFn scopeFunc) {
+	s.mux.HandleFunc("GET "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		sc, ok := scopeFn(w, r)
+		if !ok {
+			return
+		}
+
+		out := make([]*woodpecker.Secret, 0, len(s.secrets))
+		for _, secret := range s.secrets {
+			if secretScope(secret) == sc {
+				out = append(out, redactSecret(secret))
+			}
+		}
+		writeJSON(w, http.StatusOK, paginate(r, out))
+	})
+
+	s.mux.HandleFunc("POST "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		var in woodpecker.Secret
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		sc, ok := scopeFn(w, r)
+		if !ok {
+			return
+		}
+
+		if in.Name == "" || in.Value == "" || len(in.Events) == 0 {
+			http.Error(w, "Error inserting secret. Name, value and events are required", http.StatusBadRequest)
+			return
+		}
+		if s.findSecret(sc, in.Name) != nil {
+			http.Error(w, "Secret already exists", http.StatusConflict)
+			return
+		}
+
+		secret := &woodpecker.Secret{
+			ID:     s.nextID(),
+			OrgID:  sc.orgID,
+			RepoID: sc.repoID,
+			Name:   in.Name,
+			Value:  in.Value,
+			Images: nonNil(in.Images),
+			Events: in.Events,
+		}
+		s.secrets = append(s.secrets, secret)
+		writeJSON(w, http.StatusOK, redactSecret(secret))
+	})
+
+	s.mux.HandleFunc("GET "+prefix+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		secret, ok := s.pathSecret(w, r, scopeFn)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, redactSecret(secret))
+	})
+
+	s.mux.HandleFunc("PATCH "+prefix+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var in woodpecker.Secret
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		secret, ok := s.pathSecret(w, r, scopeFn)
+		if !ok {
+			return
+		}
+
+		if in.Value != "" {
+			secret.Value = in.Value
+		}
+		if in.Images != nil {
+			secret.Images = in.Images
+		}
+		if in.Events != nil {
+			secret.Events = in.Events
+		}
+		writeJSON(w, http.StatusOK, redactSecret(secret))
+	})
+
+	s.mux.HandleFunc("DELETE "+prefix+"/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		secret, ok := s.pathSecret(w, r, scopeFn)
+		if !ok {
+			return
+		}
+		s.secrets = slices.DeleteFunc(s.secrets, func(sec *woodpecker.Secret) bool {
+			return sec == secret
+		})
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// pathSecret returns the secret identified by the request scope and the name path value.
+// It must be called with s.mu held.
+func (s *Server) pathSecret(w http.ResponseWriter, r *http.Request, scopeFn scopeFunc) (*woodpecker.Secret, bool) {
+	sc, ok := scopeFn(w, r)
+	if !ok {
+		return nil, false
+	}
+	secret := s.findSecret(sc, r.PathValue("name"))
+	if secret == nil {
+		http.Error(w, "Secret not found", http.StatusNotFound)
+		return nil, false
+	}
+	return secret, true
+}
+
+func (s *Server) findSecret(sc scope, name string) *woodpecker.Secret {
+	for _, secret := range s.secrets {
+		if secretScope(secret) == sc && secret.Name == name {
+			return secret
+		}
+	}
+	return nil
+}
+
+func secretScope(secret *woodpecker.Secret) scope {
+	return scope{orgID: secret.OrgID, repoID: secret.RepoID}
+}
+
+// redactSecret returns a copy of the secret without its value, the API never returns secret values.
+func redactSecret(secret *woodpecker.Secret) *woodpecker.Secret {
+	cp := *secret
+	cp.Value = ""
+	return &cp
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return make([]T, 0)
+	}
+	return s
+}
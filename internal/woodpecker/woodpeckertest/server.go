@@ -0,0 +1,376 @@
+// Package woodpeckertest provides an in-memory fake of the Woodpecker API for tests.
+//
+// The fake implements the endpoints used by the provider (users, repositories, organizations,
+// secrets, registries, crons, agents, pipelines, logs, event streams and version) on top of net/http/httptest.
+// Repositories known to the forge are seeded with AddForgeRepo, they can then be activated
+// through the API just like on a real server.
+package woodpeckertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// DefaultVersion is the version reported by the server until SetVersion is called.
+	DefaultVersion = "3.8.0"
+	// DefaultPerPage is the page size used by list endpoints if the perPage query param is missing.
+	DefaultPerPage = 50
+	// Token is the token accepted by the server.
+	Token = "woodpeckertest-token"
+	// SelfLogin is the login of the user the Token belongs to.
+	SelfLogin = "woodpecker"
+)
+
+// Server is a fake Woodpecker server.
+type Server struct {
+	// URL is the base URL of the server, e.g. http://127.0.0.1:1234.
+	URL string
+
+	srv *httptest.Server
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	lastID   int64
+	requests int
+	faults   []int
+	version  string
+	users    []*woodpecker.User
+	orgs     []*woodpecker.Org
+	repos    []*woodpecker.Repo
+	// forgeNames holds the names of repositories renamed on the forge by ForgeRemoteID, see RenameForgeRepo
+	forgeNames map[string]string
+	// pathFaults holds status codes for requests matching "METHOD /path", see FailRequests
+	pathFaults map[string][]int
+	secrets    []*woodpecker.Secret
+	registries []*woodpecker.Registry
+	crons      []*woodpecker.Cron
+	agents     []*woodpecker.Agent
+	pipelines  map[int64][]*woodpecker.Pipeline
+	logs       map[int64][]*woodpecker.LogEntry
+	// runs holds how new pipelines of a repository are finished, see FinishPipelines
+	runs    map[int64]pipelineRun
+	events  []streamEvent
+	streams int
+	// notify is closed and replaced whenever streams have something new to send
+	notify chan struct{}
+	// drop is closed and replaced to disconnect all open streams
+	drop chan struct{}
+}
+
+// NewServer starts and returns a new Server.
+// The caller should call Close when finished, to shut it down.
+//
+// The server has a single admin user (SelfLogin) that is authenticated with Token.
+func NewServer() *Server {
+	s := &Server{
+		mux:        http.NewServeMux(),
+		version:    DefaultVersion,
+		pipelines:  make(map[int64][]*woodpecker.Pipeline),
+		logs:       make(map[int64][]*woodpecker.LogEntry),
+		runs:       make(map[int64]pipelineRun),
+		forgeNames: make(map[string]string),
+		pathFaults: make(map[string][]int),
+		notify:     make(chan struct{}),
+		drop:       make(chan struct{}),
+	}
+	s.users = append(s.users, &woodpecker.User{
+		ID:      s.nextID(),
+		ForgeID: 1,
+		Login:   SelfLogin,
+		Email:   SelfLogin + "@localhost",
+		Admin:   true,
+	})
+	s.routes()
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on this server have completed.
+func (s *Server) Close() {
+	// open streams would block Close until they're done
+	s.DropStreams()
+	s.srv.Close()
+}
+
+// Client returns a woodpecker.Client authenticated as SelfLogin.
+func (s *Server) Client() woodpecker.Client {
+	return woodpecker.NewClient(s.URL, (&oauth2.Config{}).Client(context.Background(), &oauth2.Token{
+		AccessToken: Token,
+	}))
+}
+
+// SetVersion sets the version reported by the /version endpoint.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// FailNext makes the server respond to the next n requests with the given status code.
+func (s *Server) FailNext(n int, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for range n {
+		s.faults = append(s.faults, statusCode)
+	}
+}
+
+// FailRequests makes the server respond to the next n requests with the given method and path
+// with the given status code. Other requests are served as usual.
+func (s *Server) FailRequests(method, path string, n int, statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := method + " " + path
+	for range n {
+		s.pathFaults[key] = append(s.pathFaults[key], statusCode)
+	}
+}
+
+// Requests returns the number of requests the server has received.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// AddForgeRepo registers a repository in the forge. The repository is inactive
+// (i.e. it has no Woodpecker ID) until it's activated through the API.
+func (s *Server) AddForgeRepo(owner, name string) *woodpecker.Repo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo := &woodpecker.Repo{
+		ForgeID:                      1,
+		ForgeRemoteID:                strconv.Itoa(len(s.repos) + 1),
+		Owner:                        owner,
+		Name:                         name,
+		FullName:                     owner + "/" + name,
+		ForgeURL:                     "https://forge.localhost/" + owner + "/" + name,
+		Clone:                        "https://forge.localhost/" + owner + "/" + name + ".git",
+		Branch:                       "main",
+		Timeout:                      60,
+		Visibility:                   woodpecker.VisibilityModePublic,
+		RequireApproval:              woodpecker.ApprovalModeForks,
+		AllowPullRequests:            true,
+		AllowDeployments:             false,
+		CancelPreviousPipelineEvents: make([]string, 0),
+		NetrcTrustedPlugins:          make([]string, 0),
+		ApprovalAllowedUsers:         make([]string, 0),
+	}
+	s.repos = append(s.repos, repo)
+	cp := *repo
+	return &cp
+}
+
+// RenameForgeRepo renames the repository with the given full name on the forge.
+// Like on a real server, the repository keeps its old name until it's moved through the API,
+// only the list of all repositories available on the forge returns the new name.
+func (s *Server) RenameForgeRepo(fullName, newName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, repo := range s.repos {
+		if repo.FullName == fullName {
+			s.forgeNames[repo.ForgeRemoteID] = repo.Owner + "/" + newName
+			return
+		}
+	}
+}
+
+// AddOrg registers an organization.
+func (s *Server) AddOrg(name string) *woodpecker.Org {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org := s.ensureOrg(name)
+	cp := *org
+	return &cp
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	if len(s.faults) > 0 {
+		statusCode := s.faults[0]
+		s.faults = s.faults[1:]
+		s.mu.Unlock()
+		http.Error(w, http.StatusText(statusCode), statusCode)
+		return
+	}
+	key := r.Method + " " + r.URL.Path
+	if faults := s.pathFaults[key]; len(faults) > 0 {
+		s.pathFaults[key] = faults[1:]
+		s.mu.Unlock()
+		http.Error(w, http.StatusText(faults[0]), faults[0])
+		return
+	}
+	s.mu.Unlock()
+
+	if r.URL.Path != "/version" && r.Header.Get("Authorization") != "Bearer "+Token {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// lookup endpoints take names containing slashes,
+	// so they can't be registered in the mux next to /api/repos/{repo_id}/...
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/repos/lookup/"):
+		s.repoLookup(w, strings.TrimPrefix(r.URL.Path, "/api/repos/lookup/"))
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/orgs/lookup/"):
+		s.orgLookup(w, strings.TrimPrefix(r.URL.Path, "/api/orgs/lookup/"))
+	default:
+		s.mux.ServeHTTP(w, r)
+	}
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /version", s.getVersion)
+
+	s.mux.HandleFunc("GET /api/user", s.getSelf)
+	s.mux.HandleFunc("GET /api/user/repos", s.listUserRepos)
+	s.mux.HandleFunc("GET /api/users", s.listUsers)
+	s.mux.HandleFunc("POST /api/users", s.createUser)
+	s.mux.HandleFunc("GET /api/users/{login}", s.getUser)
+	s.mux.HandleFunc("PATCH /api/users/{login}", s.updateUser)
+	s.mux.HandleFunc("DELETE /api/users/{login}", s.deleteUser)
+
+	s.mux.HandleFunc("POST /api/repos", s.activateRepo)
+	s.mux.HandleFunc("GET /api/repos/{repo_id}", s.getRepo)
+	s.mux.HandleFunc("PATCH /api/repos/{repo_id}", s.updateRepo)
+	s.mux.HandleFunc("DELETE /api/repos/{repo_id}", s.deleteRepo)
+	s.mux.HandleFunc("POST /api/repos/{repo_id}/move", s.moveRepo)
+	s.mux.HandleFunc("POST /api/repos/{repo_id}/chown", s.chownRepo)
+	s.mux.HandleFunc("POST /api/repos/{repo_id}/repair", s.repairRepo)
+	s.mux.HandleFunc("GET /api/repos/{repo_id}/permissions", s.getRepoPermissions)
+	s.handleSecrets("/api/repos/{repo_id}/secrets", s.repoScope)
+	s.handleRegistries("/api/repos/{repo_id}/registries", s.repoScope)
+	s.mux.HandleFunc("GET /api/repos/{repo_id}/pipelines", s.listPipelines)
+	s.mux.HandleFunc("POST /api/repos/{repo_id}/pipelines", s.createPipeline)
+	s.mux.HandleFunc("GET /api/repos/{repo_id}/pipelines/{number}", s.getPipeline)
+	s.mux.HandleFunc("POST /api/repos/{repo_id}/pipelines/{number}", s.restartPipeline)
+	s.mux.HandleFunc("POST /api/repos/{repo_id}/pipelines/{number}/cancel", s.cancelPipeline)
+	s.mux.HandleFunc("GET /api/repos/{repo_id}/logs/{number}/{step_id}", s.getStepLogs)
+	s.mux.HandleFunc("GET /api/repos/{repo_id}/cron", s.listCrons)
+	s.mux.HandleFunc("POST /api/repos/{repo_id}/cron", s.createCron)
+	s.mux.HandleFunc("GET /api/repos/{repo_id}/cron/{cron_id}", s.getCron)
+	s.mux.HandleFunc("PATCH /api/repos/{repo_id}/cron/{cron_id}", s.updateCron)
+	s.mux.HandleFunc("DELETE /api/repos/{repo_id}/cron/{cron_id}", s.deleteCron)
+
+	s.mux.HandleFunc("GET /api/orgs", s.listOrgs)
+	s.mux.HandleFunc("GET /api/orgs/{org_id}", s.getOrg)
+	s.mux.HandleFunc("GET /api/orgs/{org_id}/permissions", s.getOrgPermissions)
+	s.handleSecrets("/api/orgs/{org_id}/secrets", s.orgScope)
+	s.handleRegistries("/api/orgs/{org_id}/registries", s.orgScope)
+	s.handleAgents("/api/orgs/{org_id}/agents", s.orgScope)
+
+	s.handleSecrets("/api/secrets", globalScope)
+	s.handleRegistries("/api/registries", globalScope)
+	s.handleAgents("/api/agents", globalScope)
+	s.mux.HandleFunc("GET /api/agents/{agent_id}", s.getAgent)
+	s.mux.HandleFunc("GET /api/agents/{agent_id}/tasks", s.listAgentTasks)
+
+	s.mux.HandleFunc("GET /api/stream/events", s.streamEvents)
+	s.mux.HandleFunc("GET /api/stream/logs/{repo_id}/{number}/{step_id}", s.streamLogs)
+}
+
+// scope identifies the owner of secrets, registries and agents.
+// A zero scope means a global (instance-wide) object.
+type scope struct {
+	orgID  int64
+	repoID int64
+}
+
+// scopeFunc resolves the scope of the request.
+// It must be called with s.mu held and writes an error response if the scope can't be resolved.
+type scopeFunc func(w http.ResponseWriter, r *http.Request) (scope, bool)
+
+func globalScope(http.ResponseWriter, *http.Request) (scope, bool) {
+	return scope{}, true
+}
+
+func (s *Server) orgScope(w http.ResponseWriter, r *http.Request) (scope, bool) {
+	id, ok := pathID(w, r, "org_id")
+	if !ok {
+		return scope{}, false
+	}
+	if s.findOrg(id) == nil {
+		http.Error(w, "Organization not found", http.StatusNotFound)
+		return scope{}, false
+	}
+	return scope{orgID: id}, true
+}
+
+func (s *Server) repoScope(w http.ResponseWriter, r *http.Request) (scope, bool) {
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return scope{}, false
+	}
+	return scope{repoID: repo.ID}, true
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, &woodpecker.Version{
+		Source:  "https://github.com/woodpecker-ci/woodpecker",
+		Version: s.version,
+	})
+}
+
+func (s *Server) nextID() int64 {
+	s.lastID++
+	return s.lastID
+}
+
+// pathID parses the int64 path value with the given name.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing %s. %s", name, err), http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// decodeJSON decodes the request body into v.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing request. %s", err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// paginate returns the page of items requested by the page and perPage query params.
+func paginate[T any](r *http.Request, items []T) []T {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("perPage"))
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+
+	out := make([]T, 0, end-start)
+	return append(out, items[start:end]...)
+}
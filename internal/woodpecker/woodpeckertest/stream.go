@@ -0,0 +1,208 @@
+package woodpeckertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+// streamEvent is a pipeline update sent on the event stream.
+type streamEvent struct {
+	id   int64
+	data []byte
+}
+
+// DropStreams disconnects all open event and log streams, clients are expected to reconnect.
+func (s *Server) DropStreams() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	close(s.drop)
+	s.drop = make(chan struct{})
+}
+
+// OpenStreams returns the number of currently open event and log streams.
+func (s *Server) OpenStreams() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams
+}
+
+// publishPipeline sends the pipeline to the event stream.
+// It must be called with s.mu held.
+func (s *Server) publishPipeline(repo *woodpecker.Repo, pipeline *woodpecker.Pipeline) {
+	data, err := json.Marshal(woodpecker.Event{Repo: *repo, Pipeline: *pipeline})
+	if err != nil {
+		panic(err)
+	}
+	s.events = append(s.events, streamEvent{id: s.nextID(), data: data})
+	s.broadcast()
+}
+
+// broadcast wakes up all open streams.
+// It must be called with s.mu held.
+func (s *Server) broadcast() {
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+// streamEvents sends pipeline updates published after the client connected.
+// If the client sends Last-Event-ID, updates published after that event are replayed first.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := s.startStream(w)
+	if !ok {
+		return
+	}
+	defer s.endStream()
+
+	s.mu.Lock()
+	last, ok := lastEventID(r)
+	if !ok && len(s.events) > 0 {
+		last = s.events[len(s.events)-1].id
+	}
+	s.mu.Unlock()
+
+	for {
+		s.mu.Lock()
+		var pending []streamEvent
+		for _, event := range s.events {
+			if event.id > last {
+				pending = append(pending, event)
+			}
+		}
+		notify, drop := s.notify, s.drop
+		s.mu.Unlock()
+
+		for _, event := range pending {
+			_, _ = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.id, event.data)
+			last = event.id
+		}
+		flusher.Flush()
+
+		select {
+		case <-notify:
+		case <-drop:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// streamLogs sends the log of the step and follows it until the step finishes.
+// If the client sends Last-Event-ID, entries up to that id are skipped.
+func (s *Server) streamLogs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	step, ok := s.pathStep(w, r)
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	flusher, ok := s.startStream(w)
+	if !ok {
+		return
+	}
+	defer s.endStream()
+
+	last, _ := lastEventID(r)
+	for {
+		s.mu.Lock()
+		var pending []*woodpecker.LogEntry
+		for _, entry := range s.logs[step.ID] {
+			if entry.ID > last {
+				pending = append(pending, entry)
+			}
+		}
+		finished := isFinished(step.State)
+		notify, drop := s.notify, s.drop
+		s.mu.Unlock()
+
+		for _, entry := range pending {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				panic(err)
+			}
+			_, _ = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", entry.ID, data)
+			last = entry.ID
+		}
+		if finished {
+			_, _ = io.WriteString(w, "event: error\ndata: eof\n\n")
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-notify:
+		case <-drop:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// pathStep returns the step identified by the repo_id, number and step_id path values.
+// It must be called with s.mu held.
+func (s *Server) pathStep(w http.ResponseWriter, r *http.Request) (*woodpecker.Step, bool) {
+	repo, ok := s.pathRepo(w, r)
+	if !ok {
+		return nil, false
+	}
+	pipeline, ok := s.pathPipeline(w, r, repo)
+	if !ok {
+		return nil, false
+	}
+	stepID, ok := pathID(w, r, "step_id")
+	if !ok {
+		return nil, false
+	}
+
+	step := findStep(pipeline, func(step *woodpecker.Step) bool {
+		return step.ID == stepID
+	})
+	if step == nil {
+		http.Error(w, "Step not found", http.StatusNotFound)
+		return nil, false
+	}
+	return step, true
+}
+
+// startStream writes the headers of a server-sent event stream and a keep-alive comment.
+// endStream must be called when the stream is done.
+func (s *Server) startStream(w http.ResponseWriter) (http.Flusher, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	s.mu.Lock()
+	s.streams++
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, ": ping\n\n")
+	flusher.Flush()
+
+	return flusher, true
+}
+
+func (s *Server) endStream() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streams--
+}
+
+func lastEventID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	return id, err == nil
+}
@@ -0,0 +1,104 @@
+package woodpeckertest
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/Kichiyaki/terraform-provider-woodpecker/internal/woodpecker"
+)
+
+func (s *Server) getSelf(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.findUser(SelfLogin))
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, paginate(r, s.users))
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user := s.findUser(r.PathValue("login"))
+	if user == nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var in woodpecker.User
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if in.Login == "" {
+		http.Error(w, "Error inserting user. Login is required", http.StatusBadRequest)
+		return
+	}
+	if s.findUser(in.Login) != nil {
+		http.Error(w, "User already exists", http.StatusConflict)
+		return
+	}
+
+	user := &woodpecker.User{
+		ID:      s.nextID(),
+		ForgeID: 1,
+		Login:   in.Login,
+		Email:   in.Email,
+		Avatar:  in.Avatar,
+		Admin:   in.Admin,
+	}
+	s.users = append(s.users, user)
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	var in woodpecker.User
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.findUser(r.PathValue("login"))
+	if user == nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	user.Email = in.Email
+	user.Admin = in.Admin
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user := s.findUser(r.PathValue("login"))
+	if user == nil {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+	s.users = slices.DeleteFunc(s.users, func(u *woodpecker.User) bool {
+		return u == user
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) findUser(login string) *woodpecker.User {
+	for _, user := range s.users {
+		if user.Login == login {
+			return user
+		}
+	}
+	return nil
+}